@@ -3,28 +3,39 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 	"unsafe"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"moxie.demo.localhost/swap"
 )
 
 func TestMainFunction(t *testing.T) {
 	f := &os.File{}
-	swap(t, &open, func(path string) (*os.File, error) {
+	swap.Var(t, &open, func(path string) (*os.File, error) {
 		if want := "file.dat"; path != want {
 			t.Errorf("open(%q), want %q", path, want)
 		}
 		return f, nil
 	})
-	swap(t, &uploadFunc, func(r io.ReadSeeker, bucket, key string) error {
+	swap.Var(t, &uploadFunc, func(r io.ReadSeeker, bucket, key string) error {
 		if r != f || bucket != "somebucket" || key != "some/key" {
 			t.Errorf("upload(%p, %q, %q), want %p, %q, %q",
 				r, bucket, key,
@@ -32,28 +43,28 @@ func TestMainFunction(t *testing.T) {
 		}
 		return nil
 	})
-	swap(t, &os.Args, []string{"prog", "somebucket/some/key", "file.dat"})
+	swap.Var(t, &os.Args, []string{"prog", "somebucket/some/key", "file.dat"})
 
 	main()
 }
 
 func TestMainBadArgs(t *testing.T) {
 	errbuf := new(bytes.Buffer)
-	swap[io.Writer](t, &stderr, errbuf)
-	swap(t, &exit, func(code int) {
+	swap.Var[io.Writer](t, &stderr, errbuf)
+	swap.Var(t, &exit, func(code int) {
 		if want := 1; code != want {
 			t.Errorf("exit(%d), want %d", code, want)
 		}
 	})
-	swap(t, &open, func(path string) (*os.File, error) {
+	swap.Var(t, &open, func(path string) (*os.File, error) {
 		t.Errorf("open(%q), want no calls", path)
 		return nil, nil
 	})
-	swap(t, &uploadFunc, func(r io.ReadSeeker, bucket, key string) error {
+	swap.Var(t, &uploadFunc, func(r io.ReadSeeker, bucket, key string) error {
 		t.Errorf("upload(%p, %q, %q), want no calls", r, bucket, key)
 		return nil
 	})
-	swap(t, &os.Args, []string{"prog", "somebucket/some/key"})
+	swap.Var(t, &os.Args, []string{"prog", "somebucket/some/key"})
 
 	main()
 
@@ -65,23 +76,23 @@ func TestMainBadArgs(t *testing.T) {
 func TestMainBadFile(t *testing.T) {
 	errbuf := new(bytes.Buffer)
 	ferr := errors.New("no such file")
-	swap[io.Writer](t, &stderr, errbuf)
-	swap(t, &exit, func(code int) {
+	swap.Var[io.Writer](t, &stderr, errbuf)
+	swap.Var(t, &exit, func(code int) {
 		if want := 1; code != want {
 			t.Errorf("exit(%d), want %d", code, want)
 		}
 	})
-	swap(t, &open, func(path string) (*os.File, error) {
+	swap.Var(t, &open, func(path string) (*os.File, error) {
 		if want := "badfile"; path != want {
 			t.Errorf("open(%q), want %q", path, want)
 		}
 		return nil, ferr
 	})
-	swap(t, &uploadFunc, func(r io.ReadSeeker, bucket, key string) error {
+	swap.Var(t, &uploadFunc, func(r io.ReadSeeker, bucket, key string) error {
 		t.Errorf("upload(%p, %q, %q), want no calls", r, bucket, key)
 		return nil
 	})
-	swap(t, &os.Args, []string{"prog", "somebucket/some/key", "badfile"})
+	swap.Var(t, &os.Args, []string{"prog", "somebucket/some/key", "badfile"})
 
 	main()
 
@@ -92,23 +103,23 @@ func TestMainBadFile(t *testing.T) {
 
 func TestMainBadPath(t *testing.T) {
 	errbuf := new(bytes.Buffer)
-	swap[io.Writer](t, &stderr, errbuf)
-	swap(t, &exit, func(code int) {
+	swap.Var[io.Writer](t, &stderr, errbuf)
+	swap.Var(t, &exit, func(code int) {
 		if want := 1; code != want {
 			t.Errorf("exit(%d), want %d", code, want)
 		}
 	})
-	swap(t, &open, func(path string) (*os.File, error) {
+	swap.Var(t, &open, func(path string) (*os.File, error) {
 		if want := "goodfile"; path != want {
 			t.Errorf("open(%q), want %q", path, want)
 		}
 		return new(os.File), nil
 	})
-	swap(t, &uploadFunc, func(r io.ReadSeeker, bucket, key string) error {
+	swap.Var(t, &uploadFunc, func(r io.ReadSeeker, bucket, key string) error {
 		t.Errorf("upload(%p, %q, %q), want no calls", r, bucket, key)
 		return nil
 	})
-	swap(t, &os.Args, []string{"prog", "badpath", "goodfile"})
+	swap.Var(t, &os.Args, []string{"prog", "badpath", "goodfile"})
 
 	main()
 
@@ -121,19 +132,19 @@ func TestMainUploadError(t *testing.T) {
 	errbuf := new(bytes.Buffer)
 	f := new(os.File)
 	uerr := errors.New("failed to upload file")
-	swap[io.Writer](t, &stderr, errbuf)
-	swap(t, &exit, func(code int) {
+	swap.Var[io.Writer](t, &stderr, errbuf)
+	swap.Var(t, &exit, func(code int) {
 		if want := 1; code != want {
 			t.Errorf("exit(%d), want %d", code, want)
 		}
 	})
-	swap(t, &open, func(path string) (*os.File, error) {
+	swap.Var(t, &open, func(path string) (*os.File, error) {
 		if want := "goodfile"; path != want {
 			t.Errorf("open(%q), want %q", path, want)
 		}
 		return f, nil
 	})
-	swap(t, &uploadFunc, func(r io.ReadSeeker, bucket, key string) error {
+	swap.Var(t, &uploadFunc, func(r io.ReadSeeker, bucket, key string) error {
 		if r != f || bucket != "somebucket" || key != "some/key" {
 			t.Errorf("upload(%p, %q, %q), want %p, %q, %q",
 				r, bucket, key,
@@ -141,7 +152,7 @@ func TestMainUploadError(t *testing.T) {
 		}
 		return uerr
 	})
-	swap(t, &os.Args, []string{"prog", "somebucket/some/key", "goodfile"})
+	swap.Var(t, &os.Args, []string{"prog", "somebucket/some/key", "goodfile"})
 
 	main()
 
@@ -152,7 +163,7 @@ func TestMainUploadError(t *testing.T) {
 
 func TestBucketExists(t *testing.T) {
 	c := new(S3Client)
-	swap(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
 		return c
 	})
 	body := "Hello, world!"
@@ -178,7 +189,7 @@ func TestBucketExists(t *testing.T) {
 
 func TestBucketDoesNotExist(t *testing.T) {
 	c := new(S3Client)
-	swap(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
 		return c
 	})
 	body := "Hello, world!"
@@ -232,7 +243,7 @@ func TestBucketDoesNotExist(t *testing.T) {
 
 func TestBucketExistsPutFailure(t *testing.T) {
 	c := new(S3Client)
-	swap(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
 		return c
 	})
 	body := "Hello, world!"
@@ -263,7 +274,7 @@ func TestBucketExistsPutFailure(t *testing.T) {
 
 func TestBucketDoesNotExistCreateFailure(t *testing.T) {
 	c := new(S3Client)
-	swap(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
 		return c
 	})
 	body := "Hello, world!"
@@ -297,6 +308,1500 @@ func TestBucketDoesNotExistCreateFailure(t *testing.T) {
 	)
 }
 
+func TestBucketExistsExpect(t *testing.T) {
+	c := new(S3Client)
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+		return c
+	})
+	body := "Hello, world!"
+	r, bucket, key := strings.NewReader(body), "bucket", "file.txt"
+	c._PutObject_Expect().WithBody(r).WithBucket(bucket).WithKey(key).
+		Times(1).Return(nil, nil)
+
+	uerr := upload(r, bucket, key)
+
+	if uerr != nil {
+		t.Errorf("upload(%p, %q, %q) = %q, want nil", r, bucket, key, uerr)
+	}
+	c._AssertExpectations(t)
+	c._AssertTotalCalls(t, 1)
+}
+
+func TestBucketDoesNotExistExpect(t *testing.T) {
+	c := new(S3Client)
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+		return c
+	})
+	body := "Hello, world!"
+	r, bucket, key := strings.NewReader(body), "bucket", "file.txt"
+	c._PutObject_Expect().WithBucket(bucket).WithKey(key).
+		Return(nil, errors.New("NoSuchBucket"))
+	c._PutObject_Expect().WithBucket(bucket).WithKey(key).
+		Return(nil, nil)
+	c._CreateBucket_Stub()
+
+	uerr := upload(r, bucket, key)
+
+	if uerr != nil {
+		t.Errorf("upload(%p, %q, %q) = %q, want nil", r, bucket, key, uerr)
+	}
+	c._AssertExpectations(t)
+	c._AssertTotalCalls(t, 3)
+	checkEqual(t, "CreateBucket() calls", c._CreateBucket_Calls(),
+		[]_S3Client_CreateBucket_Call{
+			{Params: &s3.CreateBucketInput{Bucket: aws.String(bucket)}},
+		},
+	)
+}
+
+func TestBucketDoesNotExistAndThen(t *testing.T) {
+	c := new(S3Client)
+	_Verify(t, c)
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+		return c
+	})
+	body := "Hello, world!"
+	r, bucket, key := strings.NewReader(body), "bucket", "file.txt"
+	c._PutObject_Expect().WithBucket(bucket).
+		Return(nil, errors.New("NoSuchBucket")).
+		AndThen().Return(nil, nil)
+	c._CreateBucket_Stub()
+
+	uerr := upload(r, bucket, key)
+
+	if uerr != nil {
+		t.Errorf("upload(%p, %q, %q) = %q, want nil", r, bucket, key, uerr)
+	}
+}
+
+func TestFor(t *testing.T) {
+	var mocks [2]*S3Client
+	for i := range mocks {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+			c := _For(t, _NewS3Client)
+			c._PutObject_Stub()
+			c.PutObject(context.Background(), new(s3.PutObjectInput))
+
+			if got := _For(t, _NewS3Client); got != c {
+				t.Errorf("_For() = %p, want %p", got, c)
+			}
+			c._AssertTotalCalls(t, 1)
+			mocks[i] = c
+		})
+	}
+	t.Cleanup(func() {
+		if mocks[0] == mocks[1] {
+			t.Errorf("_For() returned %p to both subtests", mocks[0])
+		}
+		_ForMocks.Range(func(_, m any) bool {
+			if m == mocks[0] || m == mocks[1] {
+				t.Errorf("_For() kept %p after its test ended", m)
+			}
+			return true
+		})
+	})
+}
+
+func TestBucketDoesNotExistAssertConsumed(t *testing.T) {
+	c := new(S3Client)
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+		return c
+	})
+	body := "Hello, world!"
+	r, bucket, key := strings.NewReader(body), "bucket", "file.txt"
+	missing := c._PutObject_Expect().WithBucket(bucket).
+		Return(nil, errors.New("NoSuchBucket"))
+	created := missing.AndThen().Return(nil, nil)
+	c._CreateBucket_Stub()
+
+	uerr := upload(r, bucket, key)
+
+	if uerr != nil {
+		t.Errorf("upload(%p, %q, %q) = %q, want nil", r, bucket, key, uerr)
+	}
+	c._PutObject_AssertConsumed(t, missing, created)
+}
+
+func TestPutObjectAssertConsumedCallIndex(t *testing.T) {
+	c := new(S3Client)
+	a := c._PutObject_WhenBucket("a").Return(nil, nil)
+	b := c._PutObject_WhenBucket("b").Return(nil, nil)
+	ctx := context.Background()
+	for _, bucket := range []string{"c", "a", "c", "a"} {
+		c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String(bucket)})
+	}
+
+	tb := new(fakeTB)
+	c._PutObject_AssertConsumed(tb, a, b)
+
+	checkEqual(t, "_PutObject_AssertConsumed() errors", tb.errs, []string{
+		fmt.Sprintf("PutObject() call 3 handled by %s, want %s", a, b),
+	})
+}
+
+func TestPutObjectWhenMatching(t *testing.T) {
+	c := new(S3Client)
+	perr := errors.New("AccessDenied")
+	c._PutObject_WhenMatching(&s3.PutObjectInput{Bucket: aws.String("denied")}).
+		Return(nil, perr)
+	ctx := context.Background()
+
+	for bucket, want := range map[string]error{"denied": perr, "ok": nil} {
+		params := &s3.PutObjectInput{
+			Body:   strings.NewReader("Hello, world!"),
+			Bucket: aws.String(bucket),
+			Key:    aws.String("file.txt"),
+		}
+		if _, err := c.PutObject(ctx, params); err != want {
+			t.Errorf("PutObject(%q) err = %v, want %v", bucket, err, want)
+		}
+	}
+	c._AssertExpectations(t)
+}
+
+func TestPutObjectWhenMatchingNilParams(t *testing.T) {
+	c := new(S3Client)
+	perr := errors.New("AccessDenied")
+	c._PutObject_WhenMatching(&s3.PutObjectInput{Bucket: aws.String("a")}).
+		Return(nil, errors.New("unexpected"))
+	c._PutObject_WhenMatching(new(s3.PutObjectInput)).Return(nil, perr)
+
+	if _, err := c.PutObject(context.Background(), nil); err != perr {
+		t.Errorf("PutObject(nil) err = %v, want %v", err, perr)
+	}
+}
+
+func TestPutObjectWhenBucket(t *testing.T) {
+	c := new(S3Client)
+	perr, kerr := errors.New("AccessDenied"), errors.New("NoSuchKey")
+	c._PutObject_WhenBucket("denied").Return(nil, perr)
+	c._PutObject_WhenKey("missing.txt").Return(nil, kerr)
+	c._PutObject_WhenMatching(new(s3.PutObjectInput)).Return(nil, nil)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		bucket, key string
+		want        error
+	}{
+		{"denied", "file.txt", perr},
+		{"ok", "missing.txt", kerr},
+		{"ok", "file.txt", nil},
+	} {
+		params := &s3.PutObjectInput{
+			Bucket: aws.String(tt.bucket),
+			Key:    aws.String(tt.key),
+		}
+		if _, err := c.PutObject(ctx, params); err != tt.want {
+			t.Errorf("PutObject(%q, %q) err = %v, want %v",
+				tt.bucket, tt.key, err, tt.want)
+		}
+	}
+}
+
+func TestPutObjectExpectBeforeQueue(t *testing.T) {
+	c := new(S3Client)
+	perr, serr := errors.New("AccessDenied"), errors.New("SlowDown")
+	out := new(s3.PutObjectOutput)
+	c._PutObject_ReturnError(serr)
+	c._PutObject_WhenBucket("denied").Return(nil, perr)
+	c._PutObject_ReturnOutput(out)
+	ctx := context.Background()
+
+	for i, tt := range []struct {
+		bucket string
+		out    *s3.PutObjectOutput
+		err    error
+	}{
+		{"denied", nil, perr},
+		{"ok", nil, serr},
+		{"denied", nil, perr},
+		{"ok", out, nil},
+		{"ok", out, nil},
+		{"denied", nil, perr},
+	} {
+		params := &s3.PutObjectInput{Bucket: aws.String(tt.bucket)}
+		if got, err := c.PutObject(ctx, params); got != tt.out || err != tt.err {
+			t.Errorf("PutObject(%q) call %d = %v, %v, want %v, %v",
+				tt.bucket, i, got, err, tt.out, tt.err)
+		}
+	}
+	want := "S3Client{PutObject: 1 queued, 6 calls}"
+	if got := c.String(); got != want {
+		t.Errorf("S3Client.String() = %q, want %q", got, want)
+	}
+}
+
+func TestPutObjectExpectExhausted(t *testing.T) {
+	c := new(S3Client)
+	perr := errors.New("AccessDenied")
+	c._PutObject_Expect().WithBucket("a").Return(nil, nil)
+	c._PutObject_ReturnError(perr)
+	ctx := context.Background()
+	params := &s3.PutObjectInput{Bucket: aws.String("a")}
+
+	for i, want := range []error{nil, perr, perr} {
+		if _, err := c.PutObject(ctx, params); err != want {
+			t.Errorf("PutObject() call %d err = %v, want %v", i, err, want)
+		}
+	}
+}
+
+func TestPutObjectExpectDoNil(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_WhenBucket("denied").Return(nil, errors.New("AccessDenied"))
+	c._PutObject_Do(nil)
+	c._PutObject_Stub()
+	params := &s3.PutObjectInput{Bucket: aws.String("denied")}
+
+	if _, err := c.PutObject(context.Background(), params); err != nil {
+		t.Errorf("PutObject() err = %v after _PutObject_Do(nil), want nil",
+			err)
+	}
+}
+
+type tenantKey struct{}
+
+func TestPutObjectWhenCtx(t *testing.T) {
+	c := new(S3Client)
+	perr := errors.New("AccessDenied")
+	c._PutObject_WhenCtx(func(ctx context.Context, _ *s3.PutObjectInput) bool {
+		return ctx.Value(tenantKey{}) == "suspended"
+	}).Return(nil, perr)
+	c._PutObject_WhenMatching(new(s3.PutObjectInput)).
+		Return(new(s3.PutObjectOutput), nil)
+
+	for _, tenant := range []string{"suspended", "active"} {
+		ctx := context.WithValue(context.Background(), tenantKey{}, tenant)
+		want := map[string]error{"suspended": perr}[tenant]
+		params := &s3.PutObjectInput{Bucket: aws.String("bucket")}
+
+		if _, err := c.PutObject(ctx, params); err != want {
+			t.Errorf("PutObject(%q) err = %v, want %v", tenant, err, want)
+		}
+	}
+}
+
+func TestPutObjectWhenCtxReentrant(t *testing.T) {
+	c := new(S3Client)
+	e := c._PutObject_WhenCtx(func(context.Context, *s3.PutObjectInput) bool {
+		_ = c.String()
+		return len(c._PutObject_Invocations().Expectations) == 1
+	}).Return(nil, nil)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		c.PutObject(context.Background(), nil)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("PutObject() deadlocked in a WhenCtx predicate")
+	}
+	c._PutObject_AssertConsumed(t, e)
+}
+
+func TestS3ClientClone(t *testing.T) {
+	c := new(S3Client)
+	perr := errors.New("AccessDenied")
+	c._PutObject_WhenMatching(&s3.PutObjectInput{Bucket: aws.String("denied")}).
+		Return(nil, perr)
+	c._CreateBucket_Stub()
+	c.PutObject(context.Background(), new(s3.PutObjectInput))
+
+	for _, bucket := range []string{"a", "b"} {
+		t.Run(bucket, func(t *testing.T) {
+			t.Parallel()
+			c := c._Clone()
+			params := &s3.PutObjectInput{Bucket: aws.String("denied")}
+
+			_, err := c.PutObject(context.Background(), params)
+
+			if err != perr {
+				t.Errorf("PutObject() err = %v, want %v", err, perr)
+			}
+			c._AssertTotalCalls(t, 1)
+			if got := c.String(); strings.Contains(got, "CreateBucket") {
+				t.Errorf("S3Client.String() = %q, want no CreateBucket", got)
+			}
+		})
+	}
+}
+
+func TestS3ClientCollected(t *testing.T) {
+	ptrs := make([]uintptr, 50)
+	for i := range ptrs {
+		ptrs[i] = func() uintptr {
+			c := new(S3Client)
+			c._PutObject_Expect().WithKey("a").Return(nil, nil)
+			c._PutObject_DoIndexed(func(
+				int, *s3.PutObjectInput,
+			) (*s3.PutObjectOutput, error) {
+				return nil, nil
+			})
+			c._PutObject_ReturnByKey(
+				func(*s3.PutObjectInput) string { return "" },
+				map[string]_S3Client_PutObject_Result{"": {}},
+				_S3Client_PutObject_Result{},
+			)
+			c._PutObject_DoOnce(func(
+				context.Context, *s3.PutObjectInput, ...func(*s3.Options),
+			) (*s3.PutObjectOutput, error) {
+				return nil, nil
+			})
+			c._PutObject_CallThroughWhen(
+				func(*s3.PutObjectInput) bool { return false })
+			c._PutObject_RecordReal(nil, 0)
+			c.PutObject(context.Background(), new(s3.PutObjectInput))
+			c._FailFast(true)
+			return uintptr(unsafe.Pointer(c))
+		}()
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		runtime.GC()
+		var live int
+		for _, ptr := range ptrs {
+			_, dat := _S3Client.Load(ptr)
+			_, ext := _S3ClientExt.Load(ptr)
+			if dat || ext {
+				live++
+			}
+		}
+		if live == 0 {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("%d of %d mocks not collected", live, len(ptrs))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAssertAll(t *testing.T) {
+	c1, c2 := new(S3Client), new(S3Client)
+	c1._PutObject_Expect().WithKey("a").Return(nil, nil)
+	c2._PutObject_Expect().WithKey("b").Times(2).Return(nil, nil)
+	ctx := context.Background()
+
+	c1.PutObject(ctx, &s3.PutObjectInput{Key: aws.String("a")})
+	c2.PutObject(ctx, &s3.PutObjectInput{Key: aws.String("b")})
+	c2.PutObject(ctx, &s3.PutObjectInput{Key: aws.String("b")})
+
+	_AssertAll(t, c1, c2)
+}
+
+func TestAssertSameInteractions(t *testing.T) {
+	a, b := new(S3Client), new(S3Client)
+	var c *S3Client
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+		return c
+	})
+	for _, c = range []*S3Client{a, b} {
+		c._PutObject_Return(nil, errors.New("NoSuchBucket"))
+		c._CreateBucket_Stub()
+		c._PutObject_Stub()
+		if err := upload(strings.NewReader("Hello, world!"), "bucket",
+			"file.txt"); err != nil {
+			t.Fatalf("upload() = %q, want nil", err)
+		}
+	}
+
+	_AssertSameInteractions(t, a, b)
+
+	b.PutObject(context.Background(), new(s3.PutObjectInput))
+	tb := new(fakeTB)
+	_AssertSameInteractions(tb, a, b)
+	if len(tb.errs) != 1 || !strings.Contains(tb.errs[0], "PutObject") {
+		t.Errorf("_AssertSameInteractions() errors = %q, "+
+			"want one PutObject diff", tb.errs)
+	}
+
+	b._FailFast(true)
+	tb = new(fakeTB)
+	_AssertSameInteractions(tb, a, b)
+	if !tb.fatal {
+		t.Errorf("_AssertSameInteractions() with _FailFast(true): " +
+			"fatal = false, want true")
+	}
+}
+
+func TestPutObjectAssertQueueAligned(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range []struct {
+		first string
+		errs  int
+	}{
+		{first: "b", errs: 0},
+		{first: "a", errs: 2},
+	} {
+		c := new(S3Client)
+		c._PutObject_Return(nil, errors.New("NoSuchBucket"))
+		c._PutObject_Return(nil, errors.New("AccessDenied"))
+		c._PutObject_Stub()
+		c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("b")})
+		c._PutObject_Expect().WithBucket("a").Return(nil, nil)
+		for _, bucket := range []string{tt.first, "b", "b"} {
+			c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String(bucket)})
+		}
+		tb := new(fakeTB)
+
+		c._PutObject_AssertQueueAligned(tb)
+
+		if got, want := len(tb.errs), tt.errs; got != want {
+			t.Errorf("first bucket %q: _PutObject_AssertQueueAligned() "+
+				"errors = %q, want %d", tt.first, tb.errs, want)
+		}
+	}
+}
+
+func TestPutObjectExpectFor(t *testing.T) {
+	c := new(S3Client)
+	now := time.Now()
+	swap.Var(t, &_S3ClientNow, func() time.Time { return now })
+	terr := errors.New("SlowDown")
+	c._PutObject_Expect().Return(nil, terr).For(200 * time.Millisecond)
+	c._PutObject_WhenMatching(new(s3.PutObjectInput)).Return(nil, nil)
+	ctx, params := context.Background(), new(s3.PutObjectInput)
+
+	if _, err := c.PutObject(ctx, params); err != terr {
+		t.Errorf("PutObject() err = %v, want %v", err, terr)
+	}
+	now = now.Add(200 * time.Millisecond)
+	if _, err := c.PutObject(ctx, params); err != nil {
+		t.Errorf("PutObject() err = %v after 200ms, want nil", err)
+	}
+	c._AssertExpectations(t)
+}
+
+func TestPutObjectExpectAfter(t *testing.T) {
+	c := new(S3Client)
+	terr := errors.New("SlowDown")
+	c._PutObject_Expect().After(2).Return(nil, terr)
+	c._PutObject_WhenMatching(new(s3.PutObjectInput)).Return(nil, nil)
+	ctx, params := context.Background(), new(s3.PutObjectInput)
+
+	for i, want := range []error{nil, nil, terr, terr} {
+		if _, err := c.PutObject(ctx, params); err != want {
+			t.Errorf("PutObject() call %d err = %v, want %v", i, err, want)
+		}
+	}
+}
+
+func TestPutObjectExpectAfterConcurrent(t *testing.T) {
+	c := new(S3Client)
+	terr := errors.New("SlowDown")
+	c._PutObject_Expect().After(50).Return(nil, terr)
+	params := make([]*s3.PutObjectInput, 100)
+	for i := range params {
+		params[i] = new(s3.PutObjectInput)
+	}
+
+	errs := putObjectConcurrently(c, params)
+
+	for i, call := range c._PutObject_Calls() {
+		j := slices.Index(params, call.Params)
+		if got, want := errs[j] != nil, i >= 50; got != want {
+			t.Errorf("PutObject() call %d err = %v, want error %t",
+				i, errs[j], want)
+		}
+	}
+}
+
+func TestPutObjectExpectWrapf(t *testing.T) {
+	c := new(S3Client)
+	perr := errors.New("AccessDenied")
+	c._PutObject_WhenMatching(new(s3.PutObjectInput)).Return(nil, perr).
+		Wrapf("call for bucket %s", func(params *s3.PutObjectInput) any {
+			return aws.ToString(params.Bucket)
+		})
+	params := &s3.PutObjectInput{Bucket: aws.String("bucket")}
+
+	_, err := c.PutObject(context.Background(), params)
+
+	if !errors.Is(err, perr) {
+		t.Errorf("PutObject() err = %v, want wrapped %v", err, perr)
+	}
+	want := "call for bucket bucket: AccessDenied"
+	if got := fmt.Sprint(err); got != want {
+		t.Errorf("PutObject() err = %q, want %q", got, want)
+	}
+}
+
+func TestPutObjectInvocations(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Expect().WithBucket("bucket").Return(nil, nil)
+	ctx := context.Background()
+
+	c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("bucket")})
+	c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("other")})
+
+	checkEqual(t, "PutObject() invocations", c._PutObject_Invocations(),
+		_S3Client_PutObject_Invocations{
+			Expectations: []_S3Client_PutObject_Invocation{
+				{Expectation: `PutObject(Bucket: "bucket")`, Calls: 1},
+			},
+			Unmatched: 1,
+		},
+	)
+}
+
+func TestPutObjectInvocationsQueued(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Expect().WithBucket("bucket").Return(nil, nil)
+	c._PutObject_Stub()
+	ctx := context.Background()
+
+	c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("other")})
+	c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("bucket")})
+	c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("other")})
+
+	checkEqual(t, "PutObject() invocations", c._PutObject_Invocations(),
+		_S3Client_PutObject_Invocations{
+			Expectations: []_S3Client_PutObject_Invocation{
+				{Expectation: `PutObject(Bucket: "bucket")`, Calls: 1},
+			},
+			Unmatched: 2,
+		},
+	)
+}
+
+func TestPutObjectAnswer(t *testing.T) {
+	c := new(S3Client)
+	answer := func(params *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+		return &s3.PutObjectOutput{ETag: params.Key}, nil
+	}
+	c._PutObject_Answer(answer)
+	c._PutObject_Expect().WithBucket("bucket").Answer(answer)
+	ctx := context.Background()
+
+	for _, bucket := range []string{"other", "bucket"} {
+		params := &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(bucket + "/key"),
+		}
+		out, err := c.PutObject(ctx, params)
+		if err != nil {
+			t.Fatalf("PutObject(%q) err = %q, want nil", bucket, err)
+		}
+		if got, want := aws.ToString(out.ETag), bucket+"/key"; got != want {
+			t.Errorf("PutObject(%q).ETag = %q, want %q", bucket, got, want)
+		}
+	}
+}
+
+func TestS3ClientString(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Return(nil, errors.New("NoSuchBucket"))
+	c._PutObject_Stub()
+	c._CreateBucket_Stub()
+
+	c.PutObject(context.Background(), new(s3.PutObjectInput))
+
+	want := "S3Client{CreateBucket: 1 queued, 0 calls; " +
+		"PutObject: 1 queued, 1 calls}"
+	if got := c.String(); got != want {
+		t.Errorf("S3Client.String() = %q, want %q", got, want)
+	}
+}
+
+func TestBucketDoesNotExistDoIndexed(t *testing.T) {
+	c := new(S3Client)
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+		return c
+	})
+	body := "Hello, world!"
+	r, bucket, key := strings.NewReader(body), "bucket", "file.txt"
+	c._PutObject_DoIndexed(func(
+		i int, _ *s3.PutObjectInput,
+	) (*s3.PutObjectOutput, error) {
+		if i == 0 {
+			return nil, errors.New("NoSuchBucket")
+		}
+		return nil, nil
+	})
+	c._CreateBucket_Stub()
+
+	uerr := upload(r, bucket, key)
+
+	if uerr != nil {
+		t.Errorf("upload(%p, %q, %q) = %q, want nil", r, bucket, key, uerr)
+	}
+	if got, want := len(c._PutObject_Calls()), 2; got != want {
+		t.Errorf("len(PutObject() calls) = %d, want %d", got, want)
+	}
+}
+
+func TestPutObjectDoOnce(t *testing.T) {
+	c := new(S3Client)
+	var calls int
+	c._PutObject_DoOnce(func(
+		context.Context, *s3.PutObjectInput, ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error) {
+		calls++
+		return nil, nil
+	})
+	ctx := context.Background()
+	params := &s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("file.txt"),
+	}
+
+	c.PutObject(ctx, params)
+	out, err := c.PutObject(ctx, params)
+
+	if got, want := calls, 1; got != want {
+		t.Errorf("_PutObject_DoOnce() calls = %d, want %d", got, want)
+	}
+	if out != nil || err != nil {
+		t.Errorf("second PutObject() = (%v, %v), want (<nil>, <nil>)",
+			out, err)
+	}
+}
+
+func TestPutObjectReturnByKey(t *testing.T) {
+	c := new(S3Client)
+	perr := errors.New("AccessDenied")
+	merr := errors.New("NoSuchBucket")
+	c._PutObject_ReturnByKey(func(params *s3.PutObjectInput) string {
+		return aws.ToString(params.Bucket)
+	}, map[string]_S3Client_PutObject_Result{
+		"a": {Out: new(s3.PutObjectOutput)},
+		"b": {Err: perr},
+	}, _S3Client_PutObject_Result{Err: merr})
+	ctx := context.Background()
+
+	for bucket, want := range map[string]error{
+		"a": nil, "b": perr, "c": merr,
+	} {
+		params := &s3.PutObjectInput{Bucket: aws.String(bucket)}
+		if _, err := c.PutObject(ctx, params); err != want {
+			t.Errorf("PutObject(%q) err = %v, want %v", bucket, err, want)
+		}
+	}
+}
+
+func TestPutObjectRecordReal(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_RecordReal(fakeS3(t), 1)
+	params := &s3.PutObjectInput{
+		Body:   strings.NewReader("Hello, world!"),
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("file.txt"),
+	}
+
+	if _, err := c.PutObject(context.Background(), params); err != nil {
+		t.Fatalf("PutObject() err = %q, want nil", err)
+	}
+	c._PutObject_Replay()
+	out, err := c.PutObject(context.Background(), params)
+
+	if err != nil {
+		t.Fatalf("replayed PutObject() err = %q, want nil", err)
+	}
+	if got, want := aws.ToString(out.ETag), `"etag"`; got != want {
+		t.Errorf("replayed PutObject().ETag = %q, want %q", got, want)
+	}
+	if _, err := c._PutObject_RecordedJSON(); err != nil {
+		t.Errorf("_PutObject_RecordedJSON() err = %q, want nil", err)
+	}
+}
+
+func TestPutObjectReplayJSON(t *testing.T) {
+	rec := new(S3Client)
+	rec._PutObject_RecordReal(fakeS3(t), 1)
+	rec.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("file.txt"),
+	})
+	data, err := rec._PutObject_RecordedJSON()
+	if err != nil {
+		t.Fatalf("_PutObject_RecordedJSON() err = %q, want nil", err)
+	}
+	c := new(S3Client)
+
+	if err := c._PutObject_ReplayJSON(data); err != nil {
+		t.Fatalf("_PutObject_ReplayJSON() err = %q, want nil", err)
+	}
+	out, err := c.PutObject(context.Background(), new(s3.PutObjectInput))
+
+	if err != nil {
+		t.Fatalf("replayed PutObject() err = %q, want nil", err)
+	}
+	if got, want := aws.ToString(out.ETag), `"etag"`; got != want {
+		t.Errorf("replayed PutObject().ETag = %q, want %q", got, want)
+	}
+	if err := c._PutObject_ReplayJSON([]byte("{")); err == nil {
+		t.Errorf("_PutObject_ReplayJSON(%q) err = nil, want error", "{")
+	}
+}
+
+func TestPutObjectDoIndexedConcurrent(t *testing.T) {
+	c := new(S3Client)
+	var mu sync.Mutex
+	got := make(map[int]*s3.PutObjectInput)
+	c._PutObject_DoIndexed(func(
+		i int, params *s3.PutObjectInput,
+	) (*s3.PutObjectOutput, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		got[i] = params
+		return nil, nil
+	})
+	params := make([]*s3.PutObjectInput, 100)
+	for i := range params {
+		params[i] = new(s3.PutObjectInput)
+	}
+
+	putObjectConcurrently(c, params)
+
+	for i, call := range c._PutObject_Calls() {
+		if got[i] != call.Params {
+			t.Errorf("DoIndexed() call %d params = %p, want %p",
+				i, got[i], call.Params)
+		}
+	}
+}
+
+func TestPutObjectCallThroughWhen(t *testing.T) {
+	c := &S3Client{fakeS3(t)}
+	c._PutObject_CallThroughWhen(func(params *s3.PutObjectInput) bool {
+		return aws.ToString(params.Bucket) == "real"
+	})
+	ctx := context.Background()
+
+	for _, tt := range []struct{ bucket, etag string }{
+		{"real", `"etag"`},
+		{"fake", ""},
+	} {
+		params := &s3.PutObjectInput{
+			Body:   strings.NewReader("Hello, world!"),
+			Bucket: aws.String(tt.bucket),
+			Key:    aws.String("file.txt"),
+		}
+		out, err := c.PutObject(ctx, params)
+		if err != nil {
+			t.Fatalf("PutObject(%q) err = %q, want nil", tt.bucket, err)
+		}
+		var got string
+		if out != nil {
+			got = aws.ToString(out.ETag)
+		}
+		if got != tt.etag {
+			t.Errorf("PutObject(%q).ETag = %q, want %q",
+				tt.bucket, got, tt.etag)
+		}
+	}
+	checkEqual(t, "PutObject() passed through",
+		c._PutObject_PassedThrough(), []bool{true, false})
+}
+
+func TestPutObjectCallThroughWhenQueued(t *testing.T) {
+	c := &S3Client{fakeS3(t)}
+	c._PutObject_CallThroughWhen(func(params *s3.PutObjectInput) bool {
+		return aws.ToString(params.Bucket) == "real"
+	})
+	perr := errors.New("AccessDenied")
+	c._PutObject_ReturnError(perr)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		bucket, etag string
+		err          error
+	}{
+		{"fake", "", perr},
+		{"real", `"etag"`, nil},
+		{"fake", "", perr},
+	} {
+		params := &s3.PutObjectInput{
+			Bucket: aws.String(tt.bucket),
+			Key:    aws.String("file.txt"),
+		}
+		out, err := c.PutObject(ctx, params)
+		if err != tt.err {
+			t.Errorf("PutObject(%q) err = %v, want %v", tt.bucket, err, tt.err)
+		}
+		var got string
+		if out != nil {
+			got = aws.ToString(out.ETag)
+		}
+		if got != tt.etag {
+			t.Errorf("PutObject(%q).ETag = %q, want %q",
+				tt.bucket, got, tt.etag)
+		}
+	}
+	checkEqual(t, "PutObject() passed through",
+		c._PutObject_PassedThrough(), []bool{false, true, false})
+}
+
+func TestPutObjectCallThroughWhenNested(t *testing.T) {
+	c := &S3Client{fakeS3(t)}
+	ctx := context.Background()
+	fake := &s3.PutObjectInput{Bucket: aws.String("fake")}
+	c._PutObject_CallThroughWhen(func(params *s3.PutObjectInput) bool {
+		if params == fake {
+			return false
+		}
+		c.PutObject(ctx, fake) // Recorded before this call is indexed.
+		return true
+	})
+	params := &s3.PutObjectInput{
+		Bucket: aws.String("real"),
+		Key:    aws.String("file.txt"),
+	}
+
+	c.PutObject(ctx, params)
+
+	checkEqual(t, "PutObject() passed through",
+		c._PutObject_PassedThrough(), []bool{true, false})
+}
+
+func TestPutObjectReturnRoundRobin(t *testing.T) {
+	c := new(S3Client)
+	perr := errors.New("SlowDown")
+	c._PutObject_ReturnRoundRobin(
+		_S3Client_PutObject_Result{Err: perr},
+		_S3Client_PutObject_Result{},
+	)
+	ctx := context.Background()
+
+	for i, want := range []error{perr, nil, perr, nil, perr} {
+		if _, err := c.PutObject(ctx, nil); err != want {
+			t.Errorf("PutObject() call %d err = %v, want %v", i, err, want)
+		}
+	}
+}
+
+func TestPutObjectReturnRoundRobinEmpty(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("ReturnRoundRobin() did not panic")
+		}
+	}()
+	new(S3Client)._PutObject_ReturnRoundRobin()
+}
+
+func TestPutObjectReturnOnce(t *testing.T) {
+	c := new(S3Client)
+	perr := errors.New("NoSuchBucket")
+	c._PutObject_ReturnOnce(nil, perr)
+	c._PutObject_ReturnOnce(nil, nil)
+	ctx := context.Background()
+
+	for i, want := range []error{perr, nil} {
+		if _, err := c.PutObject(ctx, nil); err != want {
+			t.Errorf("PutObject() call %d err = %v, want %v", i, err, want)
+		}
+	}
+	if out, err := c.PutObject(ctx, nil); out != nil || err != nil {
+		t.Errorf("PutObject() call 2 = (%v, %v), want (<nil>, <nil>)",
+			out, err)
+	}
+}
+
+func TestPutObjectCallAccessors(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	ctx := context.Background()
+
+	c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("bucket")})
+	c.PutObject(ctx, nil)
+
+	calls := c._PutObject_Calls()
+	if got, want := calls[0].RequireBucket(t), "bucket"; got != want {
+		t.Errorf("calls[0].RequireBucket() = %q, want %q", got, want)
+	}
+	if got, want := calls[0].KeyOr("none"), "none"; got != want {
+		t.Errorf("calls[0].KeyOr(%q) = %q, want %q", want, got, want)
+	}
+	if got, want := calls[1].BucketOr("none"), "none"; got != want {
+		t.Errorf("calls[1].BucketOr(%q) = %q, want %q", want, got, want)
+	}
+}
+
+func TestBucketDoesNotExistAssertCalls(t *testing.T) {
+	c := new(S3Client)
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+		return c
+	})
+	body := "Hello, world!"
+	r, bucket, key := strings.NewReader(body), "bucket", "file.txt"
+	c._PutObject_Return(nil, errors.New("NoSuchBucket"))
+	c._CreateBucket_Stub()
+	c._PutObject_Stub()
+
+	uerr := upload(r, bucket, key)
+
+	if uerr != nil {
+		t.Errorf("upload(%p, %q, %q) = %q, want nil", r, bucket, key, uerr)
+	}
+	want := &s3.PutObjectInput{
+		Body:   strings.NewReader(body),
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	c._PutObject_AssertCalls(t, []*s3.PutObjectInput{want, want},
+		_S3ClientEquateBodies)
+	c._PutObject_AssertBody(t, 1, strings.NewReader(body))
+}
+
+func TestPutObjectAssertCallsExtraOption(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	r := strings.NewReader("Hello, world!")
+	c.PutObject(context.Background(), &s3.PutObjectInput{Body: r})
+
+	c._PutObject_AssertCalls(t, []*s3.PutObjectInput{{Body: r}})
+	c._PutObject_AssertCalls(t, []*s3.PutObjectInput{
+		{Body: strings.NewReader("Hello, world!")},
+	}, cmpopts.EquateEmpty(), _S3ClientEquateBodies)
+
+	ftb := &fakeTB{TB: t}
+	c._PutObject_AssertCalls(ftb, []*s3.PutObjectInput{
+		{Body: strings.NewReader("Hello, world!")},
+	})
+	if len(ftb.errs) != 1 {
+		t.Errorf("AssertCalls(different reader) errors = %q, want 1",
+			ftb.errs)
+	}
+}
+
+func TestPutObjectAssertCallsOptsUnchanged(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	c.PutObject(context.Background(), &s3.PutObjectInput{})
+	opts := make([]cmp.Option, 1, 2)
+	opts[0] = cmpopts.EquateEmpty()
+
+	c._PutObject_AssertCalls(t, []*s3.PutObjectInput{{}}, opts...)
+
+	if got := opts[:2][1]; got != nil {
+		t.Errorf("opts[1] = %v, want <nil>", got)
+	}
+}
+
+func TestPutObjectCallEqual(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	c.PutObject(context.Background(), &s3.PutObjectInput{
+		Body:   strings.NewReader("Hello, world!"),
+		Bucket: aws.String("bucket"),
+	})
+
+	want := []_S3Client_PutObject_Call{{Params: &s3.PutObjectInput{
+		Body:   strings.NewReader("Hello, world!"),
+		Bucket: aws.String("bucket"),
+	}}}
+	if got := c._PutObject_Calls(); !cmp.Equal(got, want) {
+		t.Errorf("PutObject() calls -want +got\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestPutObjectCheckCalledWith(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	params := &s3.PutObjectInput{Bucket: aws.String("bucket")}
+
+	c.PutObject(context.Background(), params)
+
+	c._PutObject_AssertCalledWith(t, &s3.PutObjectInput{
+		Bucket: aws.String("bucket"),
+	})
+	want := &s3.PutObjectInput{Bucket: aws.String("other")}
+	var aerr *AssertionError
+	if err := c._PutObject_CheckCalledWith(want); !errors.As(err, &aerr) {
+		t.Fatalf("_PutObject_CheckCalledWith() = %v, want *AssertionError",
+			err)
+	}
+	checkEqual(t, "AssertionError", *aerr, AssertionError{
+		Method:   "PutObject",
+		Expected: want,
+		Actual:   []*s3.PutObjectInput{params},
+	})
+}
+
+func TestPutObjectAssertOrder(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		c.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String("bucket"),
+			Key:    aws.String(key),
+		})
+	}
+
+	c._PutObject_AssertOrder(t, "a", "b", "c")
+	c._PutObject_AssertOrderBy(t, func(params *s3.PutObjectInput) string {
+		return aws.ToString(params.Bucket)
+	}, "bucket", "bucket", "bucket")
+}
+
+func TestPutObjectAssertDeadlineWithin(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c.PutObject(ctx, new(s3.PutObjectInput))
+
+	c._PutObject_AssertDeadlineWithin(t, 0, 5*time.Second)
+
+	tb := new(fakeTB)
+	c._PutObject_AssertDeadlineWithin(tb, 1, 5*time.Second)
+	if len(tb.errs) != 1 {
+		t.Errorf("_PutObject_AssertDeadlineWithin(1) errors = %q, want 1",
+			tb.errs)
+	}
+}
+
+func TestPutObjectSpy(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	spy := c._PutObject_Spy()
+	ctx := context.Background()
+
+	c.PutObject(ctx, &s3.PutObjectInput{Key: aws.String("a")})
+	c.PutObject(ctx, &s3.PutObjectInput{Key: aws.String("b")})
+
+	if got, want := spy.Count(), 2; got != want {
+		t.Errorf("spy.Count() = %d, want %d", got, want)
+	}
+	if last, ok := spy.Last(); !ok || last.KeyOr("") != "b" {
+		t.Errorf("spy.Last() = %+v, %v, want key %q, true", last, ok, "b")
+	}
+	spy.Reset()
+	if got := spy.Calls(); len(got) != 0 {
+		t.Errorf("spy.Calls() = %+v after Reset(), want none", got)
+	}
+}
+
+func TestPutObjectSpyResetExt(t *testing.T) {
+	c := &S3Client{fakeS3(t)}
+	e := c._PutObject_WhenBucket("a").Return(nil, nil)
+	c._PutObject_CallThroughWhen(func(*s3.PutObjectInput) bool { return true })
+	ctx := context.Background()
+	c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("a")})
+	c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("b")})
+
+	c._PutObject_Spy().Reset()
+	c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String("a")})
+
+	checkEqual(t, "PutObject() passed through",
+		c._PutObject_PassedThrough(), []bool{false})
+	c._PutObject_AssertConsumed(t, e)
+}
+
+func TestPutObjectRange(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	ctx := context.Background()
+	for _, key := range []string{"a", "b", "c"} {
+		c.PutObject(ctx, &s3.PutObjectInput{Key: aws.String(key)})
+	}
+
+	var got []string
+	c._PutObject_Range(func(i int, call _S3Client_PutObject_Call) bool {
+		got = append(got, fmt.Sprintf("%d:%s", i, call.KeyOr("")))
+		return call.KeyOr("") != "b"
+	})
+
+	checkEqual(t, "_PutObject_Range() keys", got, []string{"0:a", "1:b"})
+}
+
+func TestPutObjectRangeReentrant(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	ctx := context.Background()
+	c.PutObject(ctx, new(s3.PutObjectInput))
+	c.PutObject(ctx, new(s3.PutObjectInput))
+
+	var visited int
+	c._PutObject_Range(func(int, _S3Client_PutObject_Call) bool {
+		visited++
+		c.PutObject(ctx, new(s3.PutObjectInput))
+		return true
+	})
+
+	if got, want := visited, 2; got != want {
+		t.Errorf("_PutObject_Range() visited %d calls, want %d", got, want)
+	}
+	if got, want := len(c._PutObject_Calls()), 4; got != want {
+		t.Errorf("len(_PutObject_Calls()) = %d, want %d", got, want)
+	}
+}
+
+func TestPutObjectCountBy(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	ctx := context.Background()
+	for _, bucket := range []string{"a", "b", "a", "a"} {
+		c.PutObject(ctx, &s3.PutObjectInput{Bucket: aws.String(bucket)})
+	}
+
+	got := c._PutObject_CountBy(func(in *s3.PutObjectInput) string {
+		return aws.ToString(in.Bucket)
+	})
+
+	checkEqual(t, "_PutObject_CountBy() counts", got,
+		map[string]int{"a": 3, "b": 1})
+}
+
+func TestPutObjectCountByReentrant(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	c.PutObject(context.Background(), new(s3.PutObjectInput))
+
+	got := c._PutObject_CountBy(func(*s3.PutObjectInput) string {
+		return fmt.Sprint(len(c._PutObject_Calls()))
+	})
+
+	checkEqual(t, "_PutObject_CountBy() counts", got, map[string]int{"1": 1})
+}
+
+func TestPutObjectDrain(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Return(nil, errors.New("NoSuchBucket"))
+	c._PutObject_Stub()
+	c._PutObject_Stub()
+
+	c.PutObject(context.Background(), new(s3.PutObjectInput))
+	b := c._PutObject_Drain()
+
+	if got, want := len(b), 2; got != want {
+		t.Fatalf("len(_PutObject_Drain()) = %d, want %d", got, want)
+	}
+	for i, s := range []string{"_PutObject_Stub", "_PutObject_Stub"} {
+		if b[i].Index != i || !strings.Contains(b[i].Func, s) {
+			t.Errorf("_PutObject_Drain()[%d] = %v, want index %d, substr %q",
+				i, b[i], i, s)
+		}
+	}
+	if got := c._PutObject_Drain(); len(got) != 0 {
+		t.Errorf("_PutObject_Drain() = %v after drain, want none", got)
+	}
+}
+
+func TestPutObjectCallArgs(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	ctx, params := context.Background(), new(s3.PutObjectInput)
+
+	c.PutObject(ctx, params, s3OptsFunc)
+
+	gotCtx, gotParams, optFns, ok := c._PutObject_CallArgs(0)
+	if gotCtx != ctx || gotParams != params || len(optFns) != 1 || !ok {
+		t.Errorf("_PutObject_CallArgs(0) = %v, %p, %d optFns, %v, "+
+			"want %v, %p, 1 optFns, true",
+			gotCtx, gotParams, len(optFns), ok, ctx, params)
+	}
+	if _, _, _, ok := c._PutObject_CallArgs(1); ok {
+		t.Errorf("_PutObject_CallArgs(1) ok = true, want false")
+	}
+}
+
+func TestPutObjectCapturedOptions(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	ctx, params := context.Background(), new(s3.PutObjectInput)
+
+	c.PutObject(ctx, params)
+	c.PutObject(ctx, params, s3OptsFunc)
+
+	copts := cmpopts.IgnoreUnexported(s3.Options{})
+	for i, want := range []s3.Options{{}, s3opts} {
+		got, ok := c._PutObject_CapturedOptions(i)
+		if !ok {
+			t.Fatalf("_PutObject_CapturedOptions(%d) ok = false, want true", i)
+		}
+		if !cmp.Equal(want, *got, copts) {
+			t.Errorf("call %d s3.Options -want +got:\n%s",
+				i, cmp.Diff(want, *got, copts))
+		}
+	}
+	if _, ok := c._PutObject_CapturedOptions(2); ok {
+		t.Errorf("_PutObject_CapturedOptions(2) ok = true, want false")
+	}
+}
+
+func TestListObjectsAssertArgsProgression(t *testing.T) {
+	c := new(S3Client)
+	c._ListObjects_Stub()
+	ctx := context.Background()
+
+	for _, marker := range []string{"", "a", "b", "c"} {
+		c.ListObjects(ctx, &s3.ListObjectsInput{Marker: aws.String(marker)})
+	}
+
+	c._ListObjects_AssertArgsProgression(t,
+		func(prev, cur *s3.ListObjectsInput) bool {
+			return aws.ToString(prev.Marker) < aws.ToString(cur.Marker)
+		},
+	)
+}
+
+func TestPutObjectCallsJSON(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	params := &s3.PutObjectInput{
+		Body:   strings.NewReader("Hello, world!"),
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("file.txt"),
+	}
+
+	c.PutObject(context.Background(), params)
+	buf, err := c._PutObject_CallsJSON()
+
+	if err != nil {
+		t.Fatalf("_PutObject_CallsJSON() err = %q, want nil", err)
+	}
+	type input struct{ Bucket, Key string }
+	type call struct {
+		Params input
+		Body   string
+	}
+	var got []call
+	if err := json.Unmarshal(buf, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) err = %q, want nil", buf, err)
+	}
+	checkEqual(t, "PutObject() calls JSON", got, []call{
+		{input{"bucket", "file.txt"}, "Hello, world!"},
+	})
+}
+
+func TestPutObjectAssertBodyValid(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	ctx := context.Background()
+	for _, body := range []string{`{"ok": true}`, `{"ok": tru`} {
+		c.PutObject(ctx, &s3.PutObjectInput{Body: strings.NewReader(body)})
+	}
+	validJSON := func(b []byte) error {
+		var v any
+		return json.Unmarshal(b, &v)
+	}
+
+	got := c._PutObject_AssertBodyValid(t, 0, validJSON)
+
+	if want := `{"ok": true}`; string(got) != want {
+		t.Errorf("_PutObject_AssertBodyValid(0) = %q, want %q", got, want)
+	}
+	c._FailFast(true)
+	tb := new(fakeTB)
+	c._PutObject_AssertBodyValid(tb, 1, validJSON)
+	if !tb.fatal {
+		t.Errorf("_PutObject_AssertBodyValid(1) passed, want failure")
+	}
+}
+
+func TestPutObjectAssertBodyMissing(t *testing.T) {
+	c := new(S3Client)
+	c._PutObject_Stub()
+	c.PutObject(context.Background(), nil)
+
+	for _, call := range []int{0, 1} {
+		tb := new(fakeTB)
+		c._PutObject_AssertBody(tb, call, strings.NewReader(""))
+		if len(tb.errs) != 1 {
+			t.Errorf("_PutObject_AssertBody(%d) errors = %q, want 1",
+				call, tb.errs)
+		}
+	}
+}
+
+func TestPutObjectMarshalCalls(t *testing.T) {
+	child := new(S3Client)
+	child._PutObject_Stub()
+	params := &s3.PutObjectInput{
+		Body:   strings.NewReader("Hello, world!"),
+		Bucket: aws.String("bucket"),
+		Key:    aws.String("file.txt"),
+	}
+	child.PutObject(context.Background(), params, s3OptsFunc)
+	buf, err := child._PutObject_MarshalCalls()
+	if err != nil {
+		t.Fatalf("_PutObject_MarshalCalls() err = %q, want nil", err)
+	}
+
+	parent := new(S3Client)
+	if err := parent._PutObject_UnmarshalCalls(buf); err != nil {
+		t.Fatalf("_PutObject_UnmarshalCalls(%s) err = %q, want nil", buf, err)
+	}
+
+	parent._PutObject_AssertCalls(t, []*s3.PutObjectInput{params},
+		_S3ClientEquateBodies)
+	if _, _, optFns, _ := parent._PutObject_CallArgs(0); len(optFns) != 1 {
+		t.Errorf("_PutObject_CallArgs(0) optFns = %d, want 1", len(optFns))
+	}
+}
+
+func TestListObjectsPages(t *testing.T) {
+	c := new(S3Client)
+	c._ListObjects_Pages([][]types.Object{
+		{{Key: aws.String("a")}, {Key: aws.String("b")}},
+		{{Key: aws.String("c")}},
+	})
+	ctx := context.Background()
+
+	var keys []string
+	params := new(s3.ListObjectsInput)
+	for {
+		out, err := c.ListObjects(ctx, params)
+		if err != nil {
+			t.Fatalf("ListObjects() err = %q, want nil", err)
+		}
+		for _, obj := range out.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+		if !aws.ToBool(out.IsTruncated) {
+			break
+		}
+		params = &s3.ListObjectsInput{Marker: out.NextMarker}
+	}
+
+	checkEqual(t, "ListObjects() keys", keys, []string{"a", "b", "c"})
+	c._ListObjects_AssertArgsProgression(t,
+		func(prev, cur *s3.ListObjectsInput) bool {
+			return aws.ToString(prev.Marker) < aws.ToString(cur.Marker)
+		},
+	)
+}
+
+func TestBucketDoesNotExistReturnError(t *testing.T) {
+	c := new(S3Client)
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+		return c
+	})
+	body := "Hello, world!"
+	r, bucket, key := strings.NewReader(body), "bucket", "file.txt"
+	c._PutObject_ReturnError(errors.New("NoSuchBucket"))
+	c._PutObject_ReturnOutput(new(s3.PutObjectOutput))
+	c._CreateBucket_ReturnOutput(new(s3.CreateBucketOutput))
+
+	uerr := upload(r, bucket, key)
+
+	if uerr != nil {
+		t.Errorf("upload(%p, %q, %q) = %q, want nil", r, bucket, key, uerr)
+	}
+	c._AssertTotalCalls(t, 3)
+}
+
+func TestBucketDoesNotExistReturnAWSError(t *testing.T) {
+	c := new(S3Client)
+	swap.Var(t, &newS3Client, func(aws.Config, ...func(*s3.Options)) *S3Client {
+		return c
+	})
+	body := "Hello, world!"
+	r, bucket, key := strings.NewReader(body), "bucket", "file.txt"
+	c._PutObject_ReturnAWSError("NoSuchBucket")
+	c._CreateBucket_ReturnAWSError("BucketAlreadyOwnedByYou")
+
+	uerr := upload(r, bucket, key)
+
+	code := "BucketAlreadyOwnedByYou"
+	if uerr == nil || !strings.Contains(uerr.Error(), code) {
+		t.Errorf("upload(%p, %q, %q) = %v, want %s",
+			r, bucket, key, uerr, code)
+	}
+	var apiErr smithy.APIError
+	if !errors.As(uerr, &apiErr) || apiErr.ErrorCode() != code {
+		t.Errorf("upload() err = %v, want smithy.APIError with code %s",
+			uerr, code)
+	}
+}
+
+func TestPutObjectErrorIdentity(t *testing.T) {
+	errSentinel := errors.New("NoSuchBucket")
+	result := _S3Client_PutObject_Result{Err: errSentinel}
+	for name, setup := range map[string]func(*S3Client){
+		"Return": func(c *S3Client) { c._PutObject_Return(nil, errSentinel) },
+		"ReturnError": func(c *S3Client) {
+			c._PutObject_ReturnError(errSentinel)
+		},
+		"ReturnOnce": func(c *S3Client) {
+			c._PutObject_ReturnOnce(nil, errSentinel)
+		},
+		"Do": func(c *S3Client) {
+			c._PutObject_Do(func(
+				context.Context, *s3.PutObjectInput, ...func(*s3.Options),
+			) (*s3.PutObjectOutput, error) {
+				return nil, errSentinel
+			})
+		},
+		"DoIndexed": func(c *S3Client) {
+			c._PutObject_DoIndexed(func(
+				int, *s3.PutObjectInput,
+			) (*s3.PutObjectOutput, error) {
+				return nil, errSentinel
+			})
+		},
+		"Answer": func(c *S3Client) {
+			c._PutObject_Answer(func(
+				*s3.PutObjectInput,
+			) (*s3.PutObjectOutput, error) {
+				return nil, errSentinel
+			})
+		},
+		"ReturnByKey": func(c *S3Client) {
+			c._PutObject_ReturnByKey(
+				func(*s3.PutObjectInput) string { return "k" },
+				map[string]_S3Client_PutObject_Result{"k": result},
+				_S3Client_PutObject_Result{},
+			)
+		},
+		"ReturnRoundRobin": func(c *S3Client) {
+			c._PutObject_ReturnRoundRobin(result)
+		},
+		"Expect": func(c *S3Client) {
+			c._PutObject_Expect().Return(nil, errSentinel)
+		},
+		"WhenMatching": func(c *S3Client) {
+			c._PutObject_WhenMatching(new(s3.PutObjectInput)).
+				Return(nil, errSentinel)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := new(S3Client)
+			setup(c)
+
+			_, err := c.PutObject(context.Background(),
+				new(s3.PutObjectInput))
+
+			if err != errSentinel || !errors.Is(err, errSentinel) {
+				t.Errorf("PutObject() err = %#v, want %#v", err, errSentinel)
+			}
+		})
+	}
+}
+
+func TestPutObjectErrorIdentityWrapf(t *testing.T) {
+	c := new(S3Client)
+	errSentinel := errors.New("NoSuchBucket")
+	c._PutObject_Expect().Return(nil, errSentinel).Wrapf("put")
+
+	_, err := c.PutObject(context.Background(), new(s3.PutObjectInput))
+
+	if err == errSentinel || !errors.Is(err, errSentinel) {
+		t.Errorf("PutObject() err = %#v, want wrapped %#v", err, errSentinel)
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	for _, failFast := range []bool{false, true} {
+		c := new(S3Client)
+		c._FailFast(failFast)
+		tb := new(fakeTB)
+
+		c._AssertTotalCalls(tb, 1)
+
+		if got, want := tb.fatal, failFast; got != want {
+			t.Errorf("_FailFast(%v): fatal = %v, want %v", failFast, got, want)
+		}
+	}
+}
+
 func TestS3OptsFunc(t *testing.T) {
 	opts := new(s3.Options)
 	s3OptsFunc(opts)
@@ -312,11 +1817,48 @@ func TestNewS3Client(t *testing.T) {
 	}
 }
 
-func swap[T any](t *testing.T, orig *T, with T) {
-	t.Helper()
-	o := *orig
-	t.Cleanup(func() { *orig = o })
-	*orig = with
+func fakeS3(t *testing.T) *s3.Client {
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("ETag", `"etag"`)
+		},
+	))
+	t.Cleanup(srv.Close)
+	return s3.New(s3.Options{
+		BaseEndpoint: aws.String(srv.URL),
+		Credentials:  s3opts.Credentials,
+		Region:       "us-east-1",
+		UsePathStyle: true,
+	})
+}
+
+func putObjectConcurrently(
+	c *S3Client, params []*s3.PutObjectInput,
+) []error {
+	errs := make([]error, len(params))
+	var wg sync.WaitGroup
+	for i, p := range params {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = c.PutObject(context.Background(), p)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+type fakeTB struct {
+	testing.TB
+	errs  []string
+	fatal bool
+}
+
+func (*fakeTB) Helper()                  {}
+func (tb *fakeTB) Fatalf(string, ...any) { tb.fatal = true }
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.errs = append(tb.errs, fmt.Sprintf(format, args...))
 }
 
 func checkEqual[T any](t *testing.T, name string, got, want T) {
@@ -326,12 +1868,20 @@ func checkEqual[T any](t *testing.T, name string, got, want T) {
 		cmpopts.IgnoreUnexported(s3.PutObjectInput{}),
 		cmpopts.IgnoreInterfaces(struct{ context.Context }{}),
 		cmp.Comparer(ptrcmp[strings.Reader]),
+		// Bypass the call record's Equal method, which compares bodies by
+		// content, so ptrcmp checks that the caller's reader was passed on.
+		cmp.Transformer("fields",
+			func(c _S3Client_PutObject_Call) putObjectCall {
+				return putObjectCall(c)
+			}),
 	}
 	if !cmp.Equal(got, want, opts...) {
 		t.Errorf("%s -want +got\n%s", name, cmp.Diff(got, want, opts...))
 	}
 }
 
+type putObjectCall _S3Client_PutObject_Call
+
 func ptrcmp[T any](x, y *T) bool {
 	return unsafe.Pointer(x) == unsafe.Pointer(y)
 }
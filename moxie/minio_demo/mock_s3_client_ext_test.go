@@ -0,0 +1,1571 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+var (
+	_S3ClientExt = new(sync.Map)
+	_S3ClientNow = time.Now
+	_ForMocks    = new(sync.Map)
+)
+
+type _S3ClientExtData struct {
+	mutex                 sync.Mutex
+	dat                   *_S3ClientData
+	PutObjectExpectations []*_S3Client_PutObject_Expectation
+	PutObjectUnmatched    int
+	PutObjectHandled      []_S3Client_PutObject_Handled
+	PutObjectRecorded     []_S3Client_PutObject_Result
+	PutObjectPassed       map[int]bool
+	PutObjectClaimed      map[int]bool
+	PutObjectDispatch     _S3Client_PutObject_Func
+	PutObjectQueue        []_S3Client_PutObject_Func
+	PutObjectDequeued     []int
+	FailFast              atomic.Bool
+}
+
+// A _S3Client_PutObject_Handled records which expectation served a call.
+type _S3Client_PutObject_Handled struct {
+	Call        int
+	Expectation *_S3Client_PutObject_Expectation
+}
+
+type _S3Client_PutObject_Func = func(
+	context.Context, *s3.PutObjectInput, ...func(*s3.Options),
+) (*s3.PutObjectOutput, error)
+
+func _S3ClientExtPtrData(t *S3Client) *_S3ClientExtData {
+	_dat := _S3ClientPtrData(t)
+	ptr := uintptr(unsafe.Pointer(t))
+	val, loaded := _S3ClientExt.LoadOrStore(ptr, &_S3ClientExtData{dat: _dat})
+	if !loaded {
+		// An object has one finalizer, so replace the generated one with
+		// one that drops both entries. Waiting on once ensures the generated
+		// finalizer is not set after this.
+		_dat.once.Do(func() {})
+		runtime.SetFinalizer(t, nil)
+		runtime.SetFinalizer(t, func(*S3Client) {
+			_S3Client.Delete(ptr)
+			_S3ClientExt.Delete(ptr)
+		})
+	}
+	return val.(*_S3ClientExtData)
+}
+
+func (_recv *S3Client) _FailFast(on bool) {
+	if _recv == nil {
+		panic("S3Client: nil pointer receiver")
+	}
+	_S3ClientExtPtrData(_recv).FailFast.Store(on)
+}
+
+func (_recv *S3Client) _FailsFast() bool {
+	return _S3ClientExtPtrData(_recv).FailFast.Load()
+}
+
+func _S3ClientErrorf(
+	_recv *S3Client, t testing.TB, format string, args ...any,
+) {
+	t.Helper()
+	if _recv._FailsFast() {
+		t.Fatalf(format, args...)
+	} else {
+		t.Errorf(format, args...)
+	}
+}
+
+type _Expecter interface {
+	_AssertExpectations(testing.TB)
+}
+
+var _ _Expecter = (*S3Client)(nil)
+
+func _AssertAll(t testing.TB, mocks ..._Expecter) {
+	t.Helper()
+	for _, m := range mocks {
+		m._AssertExpectations(t)
+	}
+}
+
+func _Verify(t testing.TB, mocks ..._Expecter) {
+	t.Helper()
+	t.Cleanup(func() { _AssertAll(t, mocks...) })
+}
+
+// _For returns t's mock of type T, calling newFn to create it on first
+// use. Each test, including each parallel subtest, gets its own instance,
+// which is dropped when the test ends. Pass _NewS3Client as newFn instead
+// of sharing one mock across tests.
+func _For[T any](t testing.TB, newFn func() T) T {
+	key := struct {
+		t   testing.TB
+		typ reflect.Type
+	}{t, reflect.TypeFor[T]()}
+	if m, ok := _ForMocks.Load(key); ok {
+		return m.(T)
+	}
+	m, loaded := _ForMocks.LoadOrStore(key, newFn())
+	if !loaded {
+		t.Cleanup(func() { _ForMocks.Delete(key) })
+	}
+	return m.(T)
+}
+
+func _NewS3Client() *S3Client {
+	return new(S3Client)
+}
+
+type _Transcripter interface {
+	_Transcript() map[string]any
+	_FailsFast() bool
+}
+
+var _ _Transcripter = (*S3Client)(nil)
+
+func _AssertSameInteractions(t testing.TB, a, b _Transcripter) {
+	t.Helper()
+	opts := []cmp.Option{
+		cmp.FilterPath(func(p cmp.Path) bool {
+			f, ok := p.Last().(cmp.StructField)
+			return ok && !token.IsExported(f.Name())
+		}, cmp.Ignore()),
+		cmpopts.IgnoreInterfaces(struct{ context.Context }{}),
+		cmp.Comparer(func(x, y []func(*s3.Options)) bool {
+			return len(x) == len(y)
+		}),
+		_S3ClientEquateBodies,
+	}
+	diff := cmp.Diff(a._Transcript(), b._Transcript(), opts...)
+	switch {
+	case diff == "":
+	case a._FailsFast() || b._FailsFast():
+		t.Fatalf("interactions differ (-a +b):\n%s", diff)
+	default:
+		t.Errorf("interactions differ (-a +b):\n%s", diff)
+	}
+}
+
+func (_recv *S3Client) _Transcript() map[string]any {
+	if _recv == nil {
+		panic("S3Client: nil pointer receiver")
+	}
+	_dat := _S3ClientPtrData(_recv)
+	defer _dat.mutex.Unlock()
+	_dat.mutex.Lock()
+	transcript := make(map[string]any)
+	v := reflect.ValueOf(_dat).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name, ok := strings.CutSuffix(v.Type().Field(i).Name, "Calls")
+		if !ok || v.Field(i).Len() == 0 {
+			continue
+		}
+		transcript[name] = v.Field(i).Interface()
+	}
+	return transcript
+}
+
+func (_recv *S3Client) String() string {
+	if _recv == nil {
+		return "S3Client(nil)"
+	}
+	_dat := _S3ClientPtrData(_recv)
+	defer _dat.mutex.Unlock()
+	_dat.mutex.Lock()
+	_ext := _S3ClientExtPtrData(_recv)
+	_ext.mutex.Lock()
+	dispatching := _ext.putObjectSync(_dat, false)
+	putObjectQueued := len(_ext.PutObjectQueue)
+	_ext.mutex.Unlock()
+	var s []string
+	v := reflect.ValueOf(_dat).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name, ok := strings.CutSuffix(v.Type().Field(i).Name, "Calls")
+		if !ok {
+			continue
+		}
+		queued, calls := v.FieldByName(name+"Mocks").Len(), v.Field(i).Len()
+		if name == "PutObject" && dispatching {
+			queued = putObjectQueued
+		}
+		if queued > 1 {
+			queued-- // The last behavior is stored twice to make it sticky.
+		}
+		if queued == 0 && calls == 0 {
+			continue
+		}
+		s = append(s, fmt.Sprintf("%s: %d queued, %d calls",
+			name, queued, calls))
+	}
+	return "S3Client{" + strings.Join(s, "; ") + "}"
+}
+
+func (_recv *S3Client) _Clone() *S3Client {
+	if _recv == nil {
+		panic("S3Client: nil pointer receiver")
+	}
+	c := &S3Client{_recv.Client}
+	_ext := _S3ClientExtPtrData(_recv)
+	_ext.mutex.Lock()
+	var when []_S3Client_PutObject_Expectation
+	for _, e := range _ext.PutObjectExpectations {
+		if e.times < 0 {
+			when = append(when, *e)
+		}
+	}
+	_ext.mutex.Unlock()
+	for _, e := range when {
+		e.ext, e.calls = _S3ClientExtPtrData(c), 0
+		e.Answer(e.fn)
+	}
+	return c
+}
+
+func (_recv *S3Client) _TotalCalls() int {
+	if _recv == nil {
+		panic("S3Client: nil pointer receiver")
+	}
+	_dat := _S3ClientPtrData(_recv)
+	defer _dat.mutex.Unlock()
+	_dat.mutex.Lock()
+	var n int
+	v := reflect.ValueOf(_dat).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if strings.HasSuffix(v.Type().Field(i).Name, "Calls") {
+			n += v.Field(i).Len()
+		}
+	}
+	return n
+}
+
+func (_recv *S3Client) _AssertTotalCalls(t testing.TB, want int) {
+	t.Helper()
+	if got := _recv._TotalCalls(); got != want {
+		_S3ClientErrorf(_recv, t, "S3Client total calls = %d, want %d",
+			got, want)
+	}
+}
+
+func (_recv *S3Client) _CreateBucket_ReturnError(r1 error) {
+	_recv._CreateBucket_Return(nil, r1)
+}
+
+func (_recv *S3Client) _CreateBucket_ReturnOutput(r0 *s3.CreateBucketOutput) {
+	_recv._CreateBucket_Return(r0, nil)
+}
+
+func (_recv *S3Client) _PutObject_ReturnError(r1 error) {
+	_recv._PutObject_Return(nil, r1)
+}
+
+func (_recv *S3Client) _PutObject_ReturnOutput(r0 *s3.PutObjectOutput) {
+	_recv._PutObject_Return(r0, nil)
+}
+
+func (_recv *S3Client) _CreateBucket_ReturnAWSError(code string) {
+	_recv._CreateBucket_Return(nil, _S3ClientAWSError(code))
+}
+
+func (_recv *S3Client) _PutObject_ReturnAWSError(code string) {
+	_recv._PutObject_Return(nil, _S3ClientAWSError(code))
+}
+
+var _S3ClientAWSErrors = map[string]struct {
+	message string
+	fault   smithy.ErrorFault
+}{
+	"AccessDenied": {"Access Denied", smithy.FaultClient},
+	"BucketAlreadyOwnedByYou": {
+		"Your previous request to create the named bucket succeeded " +
+			"and you already own it.",
+		smithy.FaultClient,
+	},
+	"NoSuchBucket": {"The specified bucket does not exist", smithy.FaultClient},
+	"NoSuchKey":    {"The specified key does not exist.", smithy.FaultClient},
+	"SlowDown":     {"Please reduce your request rate.", smithy.FaultServer},
+}
+
+func _S3ClientAWSError(code string) error {
+	e, ok := _S3ClientAWSErrors[code]
+	if !ok {
+		e.message = code
+	}
+	return &smithy.GenericAPIError{
+		Code:    code,
+		Message: e.message,
+		Fault:   e.fault,
+	}
+}
+
+func (_recv *S3Client) _PutObject_DoIndexed(
+	fn func(int, *s3.PutObjectInput) (*s3.PutObjectOutput, error),
+) {
+	_ext := _S3ClientExtPtrData(_recv)
+	_recv._PutObject_Do(func(
+		ctx context.Context, params *s3.PutObjectInput,
+		optFns ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error) {
+		return fn(_ext.putObjectIndex(ctx, params, optFns), params)
+	})
+}
+
+type _S3Client_PutObject_Result struct {
+	Out *s3.PutObjectOutput
+	Err error
+}
+
+// _PutObject_ReturnByKey returns the result stored under the call's key,
+// or miss if there is none.
+func (_recv *S3Client) _PutObject_ReturnByKey(
+	key func(*s3.PutObjectInput) string,
+	results map[string]_S3Client_PutObject_Result,
+	miss _S3Client_PutObject_Result,
+) {
+	_recv._PutObject_Do(func(
+		_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error) {
+		if r, ok := results[key(params)]; ok {
+			return r.Out, r.Err
+		}
+		return miss.Out, miss.Err
+	})
+}
+
+// _PutObject_ReturnRoundRobin queues a behavior that cycles through
+// results, one per call. Like any behavior, it is sticky only while it is
+// the last one queued: a behavior queued after it serves the next call
+// instead. Matching expectations take precedence over it.
+func (_recv *S3Client) _PutObject_ReturnRoundRobin(
+	results ..._S3Client_PutObject_Result,
+) {
+	if len(results) == 0 {
+		panic("S3Client.PutObject: round robin with no results")
+	}
+	var i atomic.Uint64
+	_recv._PutObject_Do(func(
+		context.Context, *s3.PutObjectInput, ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error) {
+		r := results[(i.Add(1)-1)%uint64(len(results))]
+		return r.Out, r.Err
+	})
+}
+
+// _PutObject_RecordReal queues a behavior that sends its first n calls to
+// client and records the results for _PutObject_Replay and
+// _PutObject_RecordedJSON. Later calls go to the mock's Client. Like any
+// queued behavior, it serves more than one call only while it is the last
+// one queued, so queue it after everything else.
+func (_recv *S3Client) _PutObject_RecordReal(client *s3.Client, n int) {
+	var i atomic.Int64
+	_ext, fallback := _S3ClientExtPtrData(_recv), _recv.Client
+	_recv._PutObject_Do(func(
+		ctx context.Context, params *s3.PutObjectInput,
+		optFns ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error) {
+		if i.Add(1) > int64(n) {
+			return fallback.PutObject(ctx, params, optFns...)
+		}
+		out, err := client.PutObject(ctx, params, optFns...)
+		_ext.mutex.Lock()
+		_ext.PutObjectRecorded = append(_ext.PutObjectRecorded,
+			_S3Client_PutObject_Result{out, err})
+		_ext.mutex.Unlock()
+		return out, err
+	})
+}
+
+// _PutObject_Replay queues the results recorded by _PutObject_RecordReal,
+// in order.
+func (_recv *S3Client) _PutObject_Replay() {
+	_ext := _S3ClientExtPtrData(_recv)
+	_ext.mutex.Lock()
+	results := _ext.PutObjectRecorded
+	_ext.mutex.Unlock()
+	for _, r := range results {
+		_recv._PutObject_Return(r.Out, r.Err)
+	}
+}
+
+type _S3Client_PutObject_ResultJSON struct {
+	Out *s3.PutObjectOutput
+	Err *string
+}
+
+// _PutObject_RecordedJSON encodes the results recorded by
+// _PutObject_RecordReal for _PutObject_ReplayJSON. Errors keep only their
+// messages.
+func (_recv *S3Client) _PutObject_RecordedJSON() ([]byte, error) {
+	_ext := _S3ClientExtPtrData(_recv)
+	_ext.mutex.Lock()
+	var results []_S3Client_PutObject_ResultJSON
+	for _, r := range _ext.PutObjectRecorded {
+		var err *string
+		if r.Err != nil {
+			err = aws.String(r.Err.Error())
+		}
+		results = append(results, _S3Client_PutObject_ResultJSON{r.Out, err})
+	}
+	_ext.mutex.Unlock()
+	buf, err := json.MarshalIndent(results, "", "\t")
+	if err != nil {
+		return nil, fmt.Errorf("PutObject output is not serializable: %w", err)
+	}
+	return buf, nil
+}
+
+// _PutObject_ReplayJSON queues the results encoded by
+// _PutObject_RecordedJSON, in order. Errors come back as plain errors with
+// the recorded messages.
+func (_recv *S3Client) _PutObject_ReplayJSON(data []byte) error {
+	var results []_S3Client_PutObject_ResultJSON
+	if err := json.Unmarshal(data, &results); err != nil {
+		return fmt.Errorf("failed to unmarshal PutObject results: %w", err)
+	}
+	for _, r := range results {
+		var err error
+		if r.Err != nil {
+			err = errors.New(*r.Err)
+		}
+		_recv._PutObject_Return(r.Out, err)
+	}
+	return nil
+}
+
+// _PutObject_CallThroughWhen sends every call for which pred returns true
+// to the mock's Client, as set at registration. It is an expectation that
+// never runs out, not a queued behavior: calls pred rejects go on to later
+// expectations and the queue as if it were not there.
+func (_recv *S3Client) _PutObject_CallThroughWhen(
+	pred func(*s3.PutObjectInput) bool,
+) {
+	e := _recv._PutObject_Expect()
+	e.ctx = func(_ context.Context, params *s3.PutObjectInput) bool {
+		return pred(params)
+	}
+	e.times, e.through = -1, _recv.Client
+	e.Answer(func(*s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+		panic("S3Client.PutObject: call-through with nil *s3.Client")
+	})
+}
+
+func (_ext *_S3ClientExtData) putObjectIndex(
+	ctx context.Context, params *s3.PutObjectInput,
+	optFns []func(*s3.Options),
+) int {
+	defer _ext.dat.mutex.Unlock()
+	_ext.dat.mutex.Lock()
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	i, _ := _ext.putObjectClaim(ctx, params, optFns)
+	return i
+}
+
+// putObjectClaim returns the index of the call a behavior is serving. The
+// generated method records the call and releases its lock before running
+// the behavior, so other calls may be recorded in between. The call is
+// the newest unclaimed record with the same arguments; concurrent calls
+// with identical arguments may trade indices, but not records. Both
+// mutexes must be held.
+func (_ext *_S3ClientExtData) putObjectClaim(
+	ctx context.Context, params *s3.PutObjectInput,
+	optFns []func(*s3.Options),
+) (i int, claimed bool) {
+	calls, last := _ext.dat.PutObjectCalls, -1
+	for i := len(calls) - 1; i >= 0; i-- {
+		c := calls[i]
+		if c.Params != params || !_S3ClientSameCtx(c.Ctx, ctx) ||
+			len(c.OptFns) != len(optFns) ||
+			len(optFns) > 0 && &c.OptFns[0] != &optFns[0] {
+			continue
+		}
+		if _ext.PutObjectClaimed[i] {
+			if last < 0 {
+				last = i
+			}
+			continue
+		}
+		if _ext.PutObjectClaimed == nil {
+			_ext.PutObjectClaimed = make(map[int]bool)
+		}
+		_ext.PutObjectClaimed[i] = true
+		return i, true
+	}
+	if last < 0 {
+		return len(calls) - 1, false
+	}
+	return last, false
+}
+
+func _S3ClientSameCtx(a, b context.Context) bool {
+	if a == nil || b == nil || reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return a == b
+	}
+	return !reflect.TypeOf(a).Comparable() || a == b
+}
+
+// _PutObject_PassedThrough reports, for each recorded call, whether
+// _PutObject_CallThroughWhen sent it to the real client.
+func (_recv *S3Client) _PutObject_PassedThrough() []bool {
+	passed := make([]bool, len(_recv._PutObject_Calls()))
+	_ext := _S3ClientExtPtrData(_recv)
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	for i := range passed {
+		passed[i] = _ext.PutObjectPassed[i]
+	}
+	return passed
+}
+
+// _PutObject_ReturnOnce queues a single-use result. See _PutObject_DoOnce.
+func (_recv *S3Client) _PutObject_ReturnOnce(
+	r0 *s3.PutObjectOutput, r1 error,
+) {
+	_recv._PutObject_DoOnce(func(
+		context.Context, *s3.PutObjectInput, ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error) {
+		return r0, r1
+	})
+}
+
+// _PutObject_DoOnce queues fn to run for a single call. If it is the last
+// queued behavior it stays at the head of the queue once spent, and later
+// calls get zero values, the same as calls reaching an empty queue.
+func (_recv *S3Client) _PutObject_DoOnce(fn func(
+	context.Context, *s3.PutObjectInput, ...func(*s3.Options),
+) (*s3.PutObjectOutput, error)) {
+	var used atomic.Bool
+	_recv._PutObject_Do(func(
+		ctx context.Context, params *s3.PutObjectInput,
+		optFns ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error) {
+		if used.Swap(true) {
+			return nil, nil
+		}
+		return fn(ctx, params, optFns...)
+	})
+}
+
+// Equal compares Params field by field, following pointers and comparing
+// seekable bodies by content. Ctx is ignored and OptFns are compared by count.
+func (c _S3Client_PutObject_Call) Equal(o _S3Client_PutObject_Call) bool {
+	return len(c.OptFns) == len(o.OptFns) && cmp.Equal(c.Params, o.Params,
+		cmpopts.IgnoreUnexported(s3.PutObjectInput{}), _S3ClientEquateBodies)
+}
+
+// BucketOr returns the call's Bucket, or fallback if Params or Bucket is nil.
+//
+// Only Bucket and Key have accessors. The generator does not emit them, so
+// they are written by hand rather than derived from every pointer field of
+// s3.PutObjectInput. Add more here as tests need them.
+func (c _S3Client_PutObject_Call) BucketOr(fallback string) string {
+	if c.Params == nil || c.Params.Bucket == nil {
+		return fallback
+	}
+	return *c.Params.Bucket
+}
+
+// KeyOr returns the call's Key, or fallback if Params or Key is nil.
+func (c _S3Client_PutObject_Call) KeyOr(fallback string) string {
+	if c.Params == nil || c.Params.Key == nil {
+		return fallback
+	}
+	return *c.Params.Key
+}
+
+// RequireBucket returns the call's Bucket, failing t if it is nil.
+func (c _S3Client_PutObject_Call) RequireBucket(t testing.TB) string {
+	t.Helper()
+	if c.Params == nil || c.Params.Bucket == nil {
+		t.Fatalf("PutObjectInput.Bucket = <nil>, want non-nil")
+	}
+	return *c.Params.Bucket
+}
+
+// RequireKey returns the call's Key, failing t if it is nil.
+func (c _S3Client_PutObject_Call) RequireKey(t testing.TB) string {
+	t.Helper()
+	if c.Params == nil || c.Params.Key == nil {
+		t.Fatalf("PutObjectInput.Key = <nil>, want non-nil")
+	}
+	return *c.Params.Key
+}
+
+func (_recv *S3Client) _PutObject_AssertOrder(t testing.TB, keys ...string) {
+	t.Helper()
+	_recv._PutObject_AssertOrderBy(t, func(params *s3.PutObjectInput) string {
+		return aws.ToString(params.Key)
+	}, keys...)
+}
+
+func (_recv *S3Client) _PutObject_AssertOrderBy(
+	t testing.TB, fn func(*s3.PutObjectInput) string, want ...string,
+) {
+	t.Helper()
+	var got []string
+	for _, c := range _recv._PutObject_Calls() {
+		if c.Params == nil {
+			got = append(got, "<nil>")
+		} else {
+			got = append(got, fn(c.Params))
+		}
+	}
+	if !cmp.Equal(got, want, cmpopts.EquateEmpty()) {
+		_S3ClientErrorf(_recv, t, "PutObject() call order = %q, want %q",
+			got, want)
+	}
+}
+
+func (_recv *S3Client) _PutObject_AssertDeadlineWithin(
+	t testing.TB, call int, d time.Duration,
+) {
+	t.Helper()
+	ctx, _, _, ok := _recv._PutObject_CallArgs(call)
+	if !ok {
+		_S3ClientErrorf(_recv, t, "PutObject() call %d not found; %d calls",
+			call, len(_recv._PutObject_Calls()))
+		return
+	}
+	if ctx == nil {
+		_S3ClientErrorf(_recv, t,
+			"PutObject() call %d context = <nil>, want deadline", call)
+		return
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		_S3ClientErrorf(_recv, t,
+			"PutObject() call %d context has no deadline", call)
+	} else if until := time.Until(deadline); until > d {
+		_S3ClientErrorf(_recv, t,
+			"PutObject() call %d deadline in %v, want within %v",
+			call, until, d)
+	}
+}
+
+type _S3Client_PutObject_Spy struct {
+	recv *S3Client
+}
+
+func (_recv *S3Client) _PutObject_Spy() _S3Client_PutObject_Spy {
+	if _recv == nil {
+		panic("S3Client.PutObject: nil pointer receiver")
+	}
+	return _S3Client_PutObject_Spy{_recv}
+}
+
+func (s _S3Client_PutObject_Spy) Calls() []_S3Client_PutObject_Call {
+	return s.recv._PutObject_Calls()
+}
+
+func (s _S3Client_PutObject_Spy) Count() int {
+	return len(s.recv._PutObject_Calls())
+}
+
+func (s _S3Client_PutObject_Spy) Last() (_S3Client_PutObject_Call, bool) {
+	calls := s.recv._PutObject_Calls()
+	if len(calls) == 0 {
+		return _S3Client_PutObject_Call{}, false
+	}
+	return calls[len(calls)-1], true
+}
+
+// Reset forgets the recorded calls, along with which of them passed
+// through, which expectations handled them and which took queued
+// behaviors. Expectations keep their counts of calls.
+func (s _S3Client_PutObject_Spy) Reset() {
+	_dat := _S3ClientPtrData(s.recv)
+	defer _dat.mutex.Unlock()
+	_dat.mutex.Lock()
+	_dat.PutObjectCalls = nil
+	_ext := _S3ClientExtPtrData(s.recv)
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	_ext.PutObjectPassed = nil
+	_ext.PutObjectClaimed = nil
+	_ext.PutObjectHandled = nil
+	_ext.PutObjectDequeued = nil
+}
+
+type _S3Client_PutObject_Behavior struct {
+	Index int
+	Func  string
+	Fn    func(
+		context.Context, *s3.PutObjectInput, ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error)
+}
+
+func (b _S3Client_PutObject_Behavior) String() string {
+	return fmt.Sprintf("PutObject[%d]: %s", b.Index, b.Func)
+}
+
+func (_recv *S3Client) _PutObject_Drain() []_S3Client_PutObject_Behavior {
+	if _recv == nil {
+		panic("S3Client.PutObject: nil pointer receiver")
+	}
+	_dat := _S3ClientPtrData(_recv)
+	defer _dat.mutex.Unlock()
+	_dat.mutex.Lock()
+	_ext := _S3ClientExtPtrData(_recv)
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	mocks := _dat.PutObjectMocks
+	if _ext.putObjectSync(_dat, false) {
+		mocks = _ext.PutObjectQueue
+		_ext.PutObjectQueue = nil
+	} else {
+		_dat.PutObjectMocks = nil
+	}
+	if len(mocks) > 1 {
+		mocks = mocks[:len(mocks)-1]
+	}
+	var b []_S3Client_PutObject_Behavior
+	for i, fn := range mocks {
+		name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+		b = append(b, _S3Client_PutObject_Behavior{i, name, fn})
+	}
+	return b
+}
+
+func (_recv *S3Client) _PutObject_CallArgs(i int) (
+	ctx context.Context, params *s3.PutObjectInput,
+	optFns []func(*s3.Options), ok bool,
+) {
+	calls := _recv._PutObject_Calls()
+	if i < 0 || i >= len(calls) {
+		return
+	}
+	return calls[i].Ctx, calls[i].Params, calls[i].OptFns, true
+}
+
+// _PutObject_Range calls fn for each call recorded so far, in order, until
+// fn returns false. It iterates over a snapshot without holding the mock's
+// lock, so fn may use the mock; calls made meanwhile are not visited.
+func (_recv *S3Client) _PutObject_Range(
+	fn func(int, _S3Client_PutObject_Call) bool,
+) {
+	for i, call := range _recv._PutObject_Calls() {
+		if !fn(i, call) {
+			return
+		}
+	}
+}
+
+// _PutObject_CountBy tallies the calls recorded so far by key. Like
+// _PutObject_Range, it does not hold the mock's lock while calling key.
+func (_recv *S3Client) _PutObject_CountBy(
+	key func(*s3.PutObjectInput) string,
+) map[string]int {
+	counts := make(map[string]int)
+	_recv._PutObject_Range(func(_ int, call _S3Client_PutObject_Call) bool {
+		counts[key(call.Params)]++
+		return true
+	})
+	return counts
+}
+
+func (_recv *S3Client) _PutObject_CapturedOptions(i int) (*s3.Options, bool) {
+	_, _, optFns, ok := _recv._PutObject_CallArgs(i)
+	if !ok {
+		return nil, false
+	}
+	opts := new(s3.Options)
+	for _, fn := range optFns {
+		fn(opts)
+	}
+	return opts, true
+}
+
+func (_recv *S3Client) _ListObjects_AssertArgsProgression(
+	t testing.TB, ok func(prev, cur *s3.ListObjectsInput) bool,
+) {
+	t.Helper()
+	calls := _recv._ListObjects_Calls()
+	var bad []string
+	for i := 1; i < len(calls); i++ {
+		if !ok(calls[i-1].Params, calls[i].Params) {
+			bad = append(bad, fmt.Sprintf("(%d, %d)", i-1, i))
+		}
+	}
+	if len(bad) > 0 {
+		_S3ClientErrorf(_recv, t,
+			"ListObjects() calls %s violate argument progression",
+			strings.Join(bad, ", "))
+	}
+}
+
+type _S3Client_PutObject_CallJSON struct {
+	Params *s3.PutObjectInput
+	Body   *string
+	OptFns int `json:",omitempty"`
+}
+
+func (_recv *S3Client) _PutObject_CallsJSON() ([]byte, error) {
+	calls, err := _recv._PutObject_callsJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(calls, "", "\t")
+}
+
+// _PutObject_MarshalCalls encodes the recorded calls for
+// _PutObject_UnmarshalCalls. Exported Params fields survive the round
+// trip. Seekable bodies are buffered as strings and come back as
+// *strings.Reader; other bodies come back nil. OptFns survive only as a
+// count of no-op functions, and Ctx comes back as context.Background().
+func (_recv *S3Client) _PutObject_MarshalCalls() ([]byte, error) {
+	calls, err := _recv._PutObject_callsJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(calls)
+}
+
+func (_recv *S3Client) _PutObject_callsJSON() (
+	[]_S3Client_PutObject_CallJSON, error,
+) {
+	var calls []_S3Client_PutObject_CallJSON
+	for _, c := range _recv._PutObject_Calls() {
+		if c.Params == nil {
+			calls = append(calls, _S3Client_PutObject_CallJSON{
+				OptFns: len(c.OptFns),
+			})
+			continue
+		}
+		params := *c.Params
+		body, err := _S3ClientReadBody(params.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PutObjectInput.Body: %w",
+				err)
+		}
+		params.Body = nil
+		calls = append(calls, _S3Client_PutObject_CallJSON{
+			&params, body, len(c.OptFns),
+		})
+	}
+	return calls, nil
+}
+
+// _PutObject_UnmarshalCalls appends calls encoded by
+// _PutObject_MarshalCalls to the mock's history. See _PutObject_MarshalCalls
+// for what the encoding keeps.
+func (_recv *S3Client) _PutObject_UnmarshalCalls(data []byte) error {
+	if _recv == nil {
+		panic("S3Client.PutObject: nil pointer receiver")
+	}
+	var calls []_S3Client_PutObject_CallJSON
+	if err := json.Unmarshal(data, &calls); err != nil {
+		return fmt.Errorf("failed to unmarshal PutObject calls: %w", err)
+	}
+	_dat := _S3ClientPtrData(_recv)
+	defer _dat.mutex.Unlock()
+	_dat.mutex.Lock()
+	for _, c := range calls {
+		if c.Params != nil && c.Body != nil {
+			c.Params.Body = strings.NewReader(*c.Body)
+		}
+		optFns := make([]func(*s3.Options), c.OptFns)
+		for i := range optFns {
+			optFns[i] = func(*s3.Options) {}
+		}
+		_dat.PutObjectCalls = append(_dat.PutObjectCalls,
+			_S3Client_PutObject_Call{
+				Ctx:    context.Background(),
+				Params: c.Params,
+				OptFns: optFns,
+			})
+	}
+	return nil
+}
+
+var _S3ClientEquateBodies = cmp.Comparer(func(x, y io.Reader) bool {
+	if x == y {
+		return true
+	}
+	bx, errx := _S3ClientReadBody(x)
+	by, erry := _S3ClientReadBody(y)
+	if errx != nil || erry != nil || bx == nil || by == nil {
+		return false
+	}
+	return *bx == *by
+})
+
+// An AssertionError describes a failed check on a mocked method.
+type AssertionError struct {
+	Method   string
+	Expected any
+	Actual   any
+}
+
+func (e *AssertionError) Error() string {
+	return fmt.Sprintf("%s() = %+v, want %+v", e.Method, e.Actual, e.Expected)
+}
+
+func (_recv *S3Client) _PutObject_CheckCalledWith(
+	want *s3.PutObjectInput,
+) error {
+	var got []*s3.PutObjectInput
+	for _, c := range _recv._PutObject_Calls() {
+		if cmp.Equal(want, c.Params,
+			cmpopts.IgnoreUnexported(s3.PutObjectInput{}),
+			_S3ClientEquateBodies,
+		) {
+			return nil
+		}
+		got = append(got, c.Params)
+	}
+	return &AssertionError{Method: "PutObject", Expected: want, Actual: got}
+}
+
+func (_recv *S3Client) _PutObject_AssertCalledWith(
+	t testing.TB, want *s3.PutObjectInput,
+) {
+	t.Helper()
+	if err := _recv._PutObject_CheckCalledWith(want); err != nil {
+		_S3ClientErrorf(_recv, t, "%s", err)
+	}
+}
+
+// _PutObject_AssertCalls diffs the params of every recorded call against
+// want. Unexported fields of s3.PutObjectInput are always ignored. With no
+// opts, bodies are compared by identity and empty slices and maps equal nil
+// ones; passing opts replaces those two defaults, so pass
+// _S3ClientEquateBodies or another io.Reader option to compare bodies.
+func (_recv *S3Client) _PutObject_AssertCalls(
+	t testing.TB, want []*s3.PutObjectInput, opts ...cmp.Option,
+) {
+	t.Helper()
+	var got []*s3.PutObjectInput
+	for _, c := range _recv._PutObject_Calls() {
+		got = append(got, c.Params)
+	}
+	if len(opts) == 0 {
+		opts = []cmp.Option{
+			cmp.Comparer(func(x, y io.Reader) bool { return x == y }),
+			cmpopts.EquateEmpty(),
+		}
+	}
+	opts = append(slices.Clip(opts),
+		cmpopts.IgnoreUnexported(s3.PutObjectInput{}))
+	if !cmp.Equal(want, got, opts...) {
+		_S3ClientErrorf(_recv, t,
+			"PutObject() calls: %d, want %d; -want +got\n%s",
+			len(got), len(want), cmp.Diff(want, got, opts...))
+	}
+}
+
+func (_recv *S3Client) _PutObject_AssertBody(
+	t testing.TB, call int, want io.Reader,
+) {
+	t.Helper()
+	got, ok := _recv._PutObject_body(t, call)
+	if !ok {
+		return
+	}
+	buf, err := io.ReadAll(want)
+	if err != nil {
+		_S3ClientErrorf(_recv, t, "failed to read wanted body: %s", err)
+		return
+	}
+	if *got == string(buf) {
+		return
+	}
+	i := 0
+	for i < len(*got) && i < len(buf) && (*got)[i] == buf[i] {
+		i++
+	}
+	_S3ClientErrorf(_recv, t,
+		"PutObject() call %d body: %d bytes, want %d; first diff at %d",
+		call, len(*got), len(buf), i)
+}
+
+func (_recv *S3Client) _PutObject_AssertBodyValid(
+	t testing.TB, call int, valid func([]byte) error,
+) []byte {
+	t.Helper()
+	body, ok := _recv._PutObject_body(t, call)
+	if !ok {
+		return nil
+	}
+	buf := []byte(*body)
+	if err := valid(buf); err != nil {
+		_S3ClientErrorf(_recv, t,
+			"PutObject() call %d body is invalid: %s", call, err)
+	}
+	return buf
+}
+
+func (_recv *S3Client) _PutObject_body(
+	t testing.TB, call int,
+) (*string, bool) {
+	t.Helper()
+	_, params, _, ok := _recv._PutObject_CallArgs(call)
+	if !ok {
+		_S3ClientErrorf(_recv, t, "PutObject() call %d not found; %d calls",
+			call, len(_recv._PutObject_Calls()))
+		return nil, false
+	} else if params == nil {
+		_S3ClientErrorf(_recv, t, "PutObject() call %d params = <nil>", call)
+		return nil, false
+	}
+	body, err := _S3ClientReadBody(params.Body)
+	if err != nil {
+		_S3ClientErrorf(_recv, t,
+			"failed to read PutObject() call %d body: %s", call, err)
+		return nil, false
+	} else if body == nil {
+		_S3ClientErrorf(_recv, t,
+			"PutObject() call %d body is not seekable", call)
+		return nil, false
+	}
+	return body, true
+}
+
+func _S3ClientReadBody(r io.Reader) (*string, error) {
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		return nil, nil
+	}
+	pos, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	buf, err := io.ReadAll(rs)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := rs.Seek(pos, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return aws.String(string(buf)), nil
+}
+
+func (_recv *S3Client) _ListObjects_Pages(pages [][]types.Object) {
+	for i, page := range pages {
+		out := &s3.ListObjectsOutput{
+			Contents:    page,
+			IsTruncated: aws.Bool(i < len(pages)-1),
+		}
+		if len(page) > 0 && i < len(pages)-1 {
+			out.NextMarker = page[len(page)-1].Key
+		}
+		_recv._ListObjects_Return(out, nil)
+	}
+}
+
+type _S3Client_PutObject_Expectation struct {
+	ext     *_S3ClientExtData
+	body    io.Reader
+	bucket  *string
+	key     *string
+	params  *s3.PutObjectInput
+	ctx     func(context.Context, *s3.PutObjectInput) bool
+	until   time.Time
+	after   int
+	times   int
+	calls   int
+	fn      func(*s3.PutObjectInput) (*s3.PutObjectOutput, error)
+	wrap    func(*s3.PutObjectInput, error) error
+	through *s3.Client
+}
+
+func (_recv *S3Client) _PutObject_Answer(
+	fn func(*s3.PutObjectInput) (*s3.PutObjectOutput, error),
+) {
+	_recv._PutObject_Do(func(
+		_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error) {
+		return fn(params)
+	})
+}
+
+// _PutObject_Expect starts an expectation, which takes effect once it is
+// given a Return or Answer. Expectations are consulted before behaviors
+// queued with _PutObject_Do and friends, whether those were queued before
+// or after. An expectation stops matching once it has served its Times
+// calls or its For duration has passed. A call that no expectation
+// matches takes the next queued behavior, or returns zero values if there
+// is none. _PutObject_Do(nil) clears the queue and disables expectations
+// until another one is added. Behaviors queued while calls are in flight
+// may serve those calls directly.
+func (_recv *S3Client) _PutObject_Expect() *_S3Client_PutObject_Expectation {
+	if _recv == nil {
+		panic("S3Client.PutObject: nil pointer receiver")
+	}
+	return &_S3Client_PutObject_Expectation{
+		ext:   _S3ClientExtPtrData(_recv),
+		times: 1,
+	}
+}
+
+// _PutObject_WhenMatching matches calls whose params equal the non-zero
+// fields of params. A params with no fields set matches every call,
+// including calls with nil params.
+func (_recv *S3Client) _PutObject_WhenMatching(
+	params *s3.PutObjectInput,
+) *_S3Client_PutObject_Expectation {
+	e := _recv._PutObject_Expect()
+	e.params = params
+	e.times = -1
+	return e
+}
+
+// _PutObject_WhenBucket and _PutObject_WhenKey match every call with the
+// given field. Other PutObjectInput fields have no such shorthand; match
+// them with _PutObject_WhenMatching.
+func (_recv *S3Client) _PutObject_WhenBucket(
+	bucket string,
+) *_S3Client_PutObject_Expectation {
+	e := _recv._PutObject_Expect().WithBucket(bucket)
+	e.times = -1
+	return e
+}
+
+func (_recv *S3Client) _PutObject_WhenKey(
+	key string,
+) *_S3Client_PutObject_Expectation {
+	e := _recv._PutObject_Expect().WithKey(key)
+	e.times = -1
+	return e
+}
+
+// _PutObject_WhenCtx matches calls for which fn returns true. Like every
+// matcher, fn runs without the mock's lock held, so it may use the mock.
+func (_recv *S3Client) _PutObject_WhenCtx(
+	fn func(context.Context, *s3.PutObjectInput) bool,
+) *_S3Client_PutObject_Expectation {
+	e := _recv._PutObject_Expect()
+	e.ctx = fn
+	e.times = -1
+	return e
+}
+
+// WithBody, WithBucket and WithKey match the fields that upload sets.
+// To match any other PutObjectInput field, use _PutObject_WhenMatching.
+func (e *_S3Client_PutObject_Expectation) WithBody(
+	body io.Reader,
+) *_S3Client_PutObject_Expectation {
+	e.body = body
+	return e
+}
+
+func (e *_S3Client_PutObject_Expectation) WithBucket(
+	bucket string,
+) *_S3Client_PutObject_Expectation {
+	e.bucket = aws.String(bucket)
+	return e
+}
+
+func (e *_S3Client_PutObject_Expectation) WithKey(
+	key string,
+) *_S3Client_PutObject_Expectation {
+	e.key = aws.String(key)
+	return e
+}
+
+func (e *_S3Client_PutObject_Expectation) Times(
+	n int,
+) *_S3Client_PutObject_Expectation {
+	_ext := e.ext
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	e.times, e.until = n, time.Time{}
+	return e
+}
+
+// For makes the expectation match any number of calls until d has passed.
+// For and Times each replace the limit set by the other, so whichever is
+// called last wins.
+func (e *_S3Client_PutObject_Expectation) For(
+	d time.Duration,
+) *_S3Client_PutObject_Expectation {
+	_ext := e.ext
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	e.times, e.until = -1, _S3ClientNow().Add(d)
+	return e
+}
+
+// After makes the expectation skip the mock's first n PutObject calls,
+// whether or not they would have matched it, and match any number of
+// calls after that; call Times after After to limit them. Expectations
+// are tried in the order they were added, so a call After skips goes to
+// the next matching expectation, then to the queued behaviors.
+func (e *_S3Client_PutObject_Expectation) After(
+	n int,
+) *_S3Client_PutObject_Expectation {
+	_ext := e.ext
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	e.after, e.times = n, -1
+	return e
+}
+
+func (e *_S3Client_PutObject_Expectation) Return(
+	r0 *s3.PutObjectOutput, r1 error,
+) *_S3Client_PutObject_Expectation {
+	return e.Answer(func(*s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+		return r0, r1
+	})
+}
+
+func (e *_S3Client_PutObject_Expectation) Answer(
+	fn func(*s3.PutObjectInput) (*s3.PutObjectOutput, error),
+) *_S3Client_PutObject_Expectation {
+	e.fn = fn
+	_ext := e.ext
+	_ext.mutex.Lock()
+	_ext.PutObjectExpectations = append(_ext.PutObjectExpectations, e)
+	_ext.mutex.Unlock()
+	_ext.putObjectInstall()
+	return e
+}
+
+// putObjectInstall puts the expectation dispatcher at the head of the
+// generated PutObject queue. Behaviors already queued move behind it, into
+// PutObjectQueue, so expectations are consulted first on every call.
+func (_ext *_S3ClientExtData) putObjectInstall() {
+	_dat := _ext.dat
+	defer _dat.mutex.Unlock()
+	_dat.mutex.Lock()
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	if _ext.putObjectSync(_dat, false) {
+		return
+	}
+	if _ext.PutObjectDispatch == nil {
+		_ext.PutObjectDispatch = _ext.putObjectDispatcher(_dat)
+		// Until now every call took the next queued behavior in turn.
+		for i := range _dat.PutObjectCalls {
+			_ext.PutObjectDequeued = append(_ext.PutObjectDequeued, i)
+		}
+	}
+	_ext.PutObjectQueue = slices.Clone(_dat.PutObjectMocks)
+	_dat.PutObjectMocks = []_S3Client_PutObject_Func{
+		_ext.PutObjectDispatch, _ext.PutObjectDispatch,
+	}
+}
+
+// putObjectSync moves behaviors queued behind the dispatcher into
+// PutObjectQueue, with the same consumption rules as the generated queue,
+// and puts the dispatcher back at the head. It reports false if the
+// dispatcher is not installed, for instance after _PutObject_Do(nil).
+// Both mutexes must be held.
+func (_ext *_S3ClientExtData) putObjectSync(
+	_dat *_S3ClientData, popped bool,
+) bool {
+	mocks := _dat.PutObjectMocks
+	if !popped && (len(mocks) == 0 || !_ext.putObjectIsDispatch(mocks[0])) {
+		return false
+	}
+	for len(mocks) > 0 && _ext.putObjectIsDispatch(mocks[0]) {
+		mocks = mocks[1:]
+	}
+	if len(mocks) > 0 {
+		queue := _ext.PutObjectQueue
+		if len(queue) < 2 {
+			queue = nil
+		} else {
+			queue = slices.Clip(queue[:len(queue)-1])
+		}
+		_ext.PutObjectQueue = append(queue, mocks...)
+	}
+	_dat.PutObjectMocks = []_S3Client_PutObject_Func{
+		_ext.PutObjectDispatch, _ext.PutObjectDispatch,
+	}
+	return true
+}
+
+func (_ext *_S3ClientExtData) putObjectIsDispatch(
+	fn _S3Client_PutObject_Func,
+) bool {
+	return _ext.PutObjectDispatch != nil && reflect.ValueOf(fn).Pointer() ==
+		reflect.ValueOf(_ext.PutObjectDispatch).Pointer()
+}
+
+func (_ext *_S3ClientExtData) putObjectDispatcher(
+	_dat *_S3ClientData,
+) _S3Client_PutObject_Func {
+	return func(
+		ctx context.Context, params *s3.PutObjectInput,
+		optFns ...func(*s3.Options),
+	) (r0 *s3.PutObjectOutput, r1 error) {
+		_dat.mutex.Lock()
+		_ext.mutex.Lock()
+		_ext.putObjectSync(_dat, true)
+		i, claimed := _ext.putObjectClaim(ctx, params, optFns)
+		_dat.mutex.Unlock()
+		var live []*_S3Client_PutObject_Expectation
+		now := _S3ClientNow()
+		for _, x := range _ext.PutObjectExpectations {
+			if !x.until.IsZero() && !now.Before(x.until) {
+				continue
+			}
+			if i < x.after {
+				continue
+			}
+			if x.times >= 0 && x.calls >= x.times {
+				continue
+			}
+			live = append(live, x)
+		}
+		_ext.mutex.Unlock()
+		// Matchers may call into the mock, so they run without its lock.
+		// An expectation that another call exhausted meanwhile is skipped.
+		var hit *_S3Client_PutObject_Expectation
+		for _, x := range live {
+			if !x.match(ctx, params) {
+				continue
+			}
+			_ext.mutex.Lock()
+			if x.times < 0 || x.calls < x.times {
+				hit = x
+				break
+			}
+			_ext.mutex.Unlock()
+		}
+		if hit == nil {
+			_ext.mutex.Lock()
+			_ext.PutObjectUnmatched++
+			if claimed { // The queued behavior may claim the call itself.
+				delete(_ext.PutObjectClaimed, i)
+			}
+			queue := _ext.PutObjectQueue
+			if len(queue) == 0 {
+				_ext.mutex.Unlock()
+				return r0, r1
+			}
+			if len(queue) > 1 {
+				_ext.PutObjectQueue = queue[1:]
+				_ext.PutObjectDequeued = append(_ext.PutObjectDequeued, i)
+			}
+			_ext.mutex.Unlock()
+			return queue[0](ctx, params, optFns...)
+		}
+		hit.calls++
+		_ext.PutObjectHandled = append(_ext.PutObjectHandled,
+			_S3Client_PutObject_Handled{i, hit})
+		fn, wrap, through := hit.fn, hit.wrap, hit.through
+		if through != nil {
+			if _ext.PutObjectPassed == nil {
+				_ext.PutObjectPassed = make(map[int]bool)
+			}
+			_ext.PutObjectPassed[i] = true
+		}
+		_ext.mutex.Unlock()
+		if through != nil {
+			return through.PutObject(ctx, params, optFns...)
+		}
+		r0, r1 = fn(params)
+		if r1 != nil && wrap != nil {
+			r1 = wrap(params, r1)
+		}
+		return r0, r1
+	}
+}
+
+func (e *_S3Client_PutObject_Expectation) Wrapf(
+	format string, args ...func(*s3.PutObjectInput) any,
+) *_S3Client_PutObject_Expectation {
+	_ext := e.ext
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	e.wrap = func(params *s3.PutObjectInput, err error) error {
+		a := make([]any, 0, len(args)+1)
+		for _, arg := range args {
+			a = append(a, arg(params))
+		}
+		return fmt.Errorf(format+": %w", append(a, err)...)
+	}
+	return e
+}
+
+func (e *_S3Client_PutObject_Expectation) AndThen() *_S3Client_PutObject_Expectation {
+	return &_S3Client_PutObject_Expectation{
+		ext:    e.ext,
+		body:   e.body,
+		bucket: e.bucket,
+		key:    e.key,
+		params: e.params,
+		ctx:    e.ctx,
+		times:  1,
+	}
+}
+
+func (e *_S3Client_PutObject_Expectation) match(
+	ctx context.Context, params *s3.PutObjectInput,
+) bool {
+	if e.ctx != nil && !e.ctx(ctx, params) {
+		return false
+	}
+	if params == nil {
+		return e.body == nil && e.bucket == nil && e.key == nil &&
+			(e.params == nil || cmp.Equal(e.params, new(s3.PutObjectInput),
+				_S3ClientPartial...))
+	}
+	if e.body != nil && params.Body != e.body {
+		return false
+	}
+	if e.bucket != nil && aws.ToString(params.Bucket) != *e.bucket {
+		return false
+	}
+	if e.key != nil && aws.ToString(params.Key) != *e.key {
+		return false
+	}
+	if e.params != nil && !cmp.Equal(e.params, params, _S3ClientPartial...) {
+		return false
+	}
+	return true
+}
+
+var _S3ClientPartial = []cmp.Option{
+	cmpopts.IgnoreUnexported(s3.PutObjectInput{}),
+	cmp.Comparer(func(x, y io.Reader) bool { return x == y }),
+	cmp.FilterPath(func(p cmp.Path) bool {
+		want, _ := p.Last().Values()
+		return want.IsValid() && want.IsZero()
+	}, cmp.Ignore()),
+}
+
+func (e *_S3Client_PutObject_Expectation) String() string {
+	var s []string
+	if e.body != nil {
+		s = append(s, fmt.Sprintf("Body: %p", e.body))
+	}
+	if e.bucket != nil {
+		s = append(s, fmt.Sprintf("Bucket: %q", *e.bucket))
+	}
+	if e.key != nil {
+		s = append(s, fmt.Sprintf("Key: %q", *e.key))
+	}
+	if e.params != nil {
+		v := reflect.ValueOf(e.params).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() || v.Field(i).IsZero() {
+				continue
+			}
+			s = append(s, fmt.Sprintf("%s: %#v",
+				f.Name, reflect.Indirect(v.Field(i))))
+		}
+	}
+	if e.after > 0 {
+		s = append(s, fmt.Sprintf("After: %d", e.after))
+	}
+	if e.ctx != nil {
+		s = append(s, "Ctx: "+runtime.FuncForPC(
+			reflect.ValueOf(e.ctx).Pointer()).Name())
+	}
+	return "PutObject(" + strings.Join(s, ", ") + ")"
+}
+
+type _S3Client_PutObject_Invocations struct {
+	Expectations []_S3Client_PutObject_Invocation
+	Unmatched    int
+}
+
+type _S3Client_PutObject_Invocation struct {
+	Expectation string
+	Calls       int
+}
+
+func (_recv *S3Client) _PutObject_Invocations() _S3Client_PutObject_Invocations {
+	if _recv == nil {
+		panic("S3Client.PutObject: nil pointer receiver")
+	}
+	_ext := _S3ClientExtPtrData(_recv)
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	var inv _S3Client_PutObject_Invocations
+	for _, e := range _ext.PutObjectExpectations {
+		inv.Expectations = append(inv.Expectations,
+			_S3Client_PutObject_Invocation{e.String(), e.calls})
+	}
+	inv.Unmatched = _ext.PutObjectUnmatched
+	return inv
+}
+
+// _PutObject_AssertQueueAligned checks that each queued behavior was taken
+// by the call whose index matches the behavior's position in the queue. A
+// mismatch usually means an expectation served a call the setup meant for
+// the queue. Calls reusing the sticky last behavior are not checked.
+func (_recv *S3Client) _PutObject_AssertQueueAligned(t testing.TB) {
+	t.Helper()
+	_ext := _S3ClientExtPtrData(_recv)
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	for pos, call := range _ext.PutObjectDequeued {
+		if call != pos {
+			_S3ClientErrorf(_recv, t,
+				"PutObject() queued behavior %d taken by call %d, want %d",
+				pos, call, pos)
+		}
+	}
+}
+
+func (_recv *S3Client) _PutObject_AssertConsumed(
+	t testing.TB, want ...*_S3Client_PutObject_Expectation,
+) {
+	t.Helper()
+	_ext := _S3ClientExtPtrData(_recv)
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	got := _ext.PutObjectHandled
+	for i := 0; i < len(got) || i < len(want); i++ {
+		switch {
+		case i >= len(got):
+			_S3ClientErrorf(_recv, t,
+				"PutObject() expectations consumed %d times, want %s next",
+				len(got), want[i])
+		case i >= len(want):
+			_S3ClientErrorf(_recv, t,
+				"PutObject() call %d handled by %s, want no more",
+				got[i].Call, got[i].Expectation)
+		case got[i].Expectation != want[i]:
+			_S3ClientErrorf(_recv, t,
+				"PutObject() call %d handled by %s, want %s",
+				got[i].Call, got[i].Expectation, want[i])
+		default:
+			continue
+		}
+		return
+	}
+}
+
+func (_recv *S3Client) _AssertExpectations(t testing.TB) {
+	t.Helper()
+	if _recv == nil {
+		panic("S3Client: nil pointer receiver")
+	}
+	_ext := _S3ClientExtPtrData(_recv)
+	defer _ext.mutex.Unlock()
+	_ext.mutex.Lock()
+	for _, e := range _ext.PutObjectExpectations {
+		if e.times >= 0 && e.calls != e.times {
+			_S3ClientErrorf(_recv, t, "%s called %d times, want %d",
+				e, e.calls, e.times)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Package swap replaces package-level variables for the duration of a test.
+package swap
+
+import "testing"
+
+// Var sets *orig to with and restores the original value when t finishes.
+func Var[T any](t testing.TB, orig *T, with T) {
+	t.Helper()
+	o := *orig
+	t.Cleanup(func() { *orig = o })
+	*orig = with
+}
+
+// A Swap is a replacement created by [Of] and applied by [Vars].
+type Swap func(testing.TB)
+
+// Of returns a Swap that sets *orig to with.
+func Of[T any](orig *T, with T) Swap {
+	return func(t testing.TB) {
+		t.Helper()
+		Var(t, orig, with)
+	}
+}
+
+// Vars applies each swap in order.
+func Vars(t testing.TB, swaps ...Swap) {
+	t.Helper()
+	for _, s := range swaps {
+		s(t)
+	}
+}
@@ -0,0 +1,29 @@
+package swap
+
+import "testing"
+
+func TestVar(t *testing.T) {
+	x := 1
+	t.Run("swap", func(t *testing.T) {
+		Var(t, &x, 2)
+		if got, want := x, 2; got != want {
+			t.Errorf("x = %d, want %d", got, want)
+		}
+	})
+	if got, want := x, 1; got != want {
+		t.Errorf("x = %d after test, want %d", got, want)
+	}
+}
+
+func TestVars(t *testing.T) {
+	x, s := 1, "a"
+	t.Run("swap", func(t *testing.T) {
+		Vars(t, Of(&x, 2), Of(&s, "b"))
+		if x != 2 || s != "b" {
+			t.Errorf("x, s = %d, %q, want %d, %q", x, s, 2, "b")
+		}
+	})
+	if x != 1 || s != "a" {
+		t.Errorf("x, s = %d, %q after test, want %d, %q", x, s, 1, "a")
+	}
+}